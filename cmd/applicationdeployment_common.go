@@ -17,6 +17,7 @@ var (
 	flagVersion      string
 	flagCluster      string
 	flagExcludes     []string
+	flagSelector     string
 )
 
 var applicationDeploymentCmd = &cobra.Command{
@@ -122,3 +123,31 @@ func getApplicationDeploymentClient(partition Partition) client.ApplicationDeplo
 
 	return cli
 }
+
+// getApplicationSearch returns the applications to search for, given as env/app or env app.
+// The search can be left out when a selector is given, and then matches all applications.
+func getApplicationSearch(args []string) (string, bool) {
+	if len(args) > 2 || (len(args) < 1 && flagSelector == "") {
+		return "", false
+	}
+	switch len(args) {
+	case 0:
+		return "", true
+	case 2:
+		return fmt.Sprintf("%s/%s", args[0], args[1]), true
+	}
+	return args[0], true
+}
+
+// selectDeploymentSpecs returns the deployment specs matching flagSelector
+func selectDeploymentSpecs(specs []deploymentspec.DeploymentSpec) ([]deploymentspec.DeploymentSpec, error) {
+	selector, err := deploymentspec.ParseSelector(flagSelector)
+	if err != nil {
+		return nil, err
+	}
+	return selector.Filter(specs), nil
+}
+
+func addSelectorFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&flagSelector, "selector", "", "Select applications by deploy spec fields, e.g. 'cluster=utv04,type=deploy,version!~SNAPSHOT'")
+}
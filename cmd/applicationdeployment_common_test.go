@@ -67,3 +67,30 @@ func Test_createRequestPartitionsWithOverrideToken(t *testing.T) {
 
 	assert.Equal(t, overrideToken, samplePartition.OverrideToken)
 }
+
+func Test_getApplicationSearch(t *testing.T) {
+	defer func() { flagSelector = "" }()
+
+	search, ok := getApplicationSearch([]string{"dev", "crm"})
+	assert.True(t, ok)
+	assert.Equal(t, "dev/crm", search)
+
+	_, ok = getApplicationSearch([]string{})
+	assert.False(t, ok)
+
+	flagSelector = "cluster=east"
+	search, ok = getApplicationSearch([]string{})
+	assert.True(t, ok)
+	assert.Equal(t, "", search)
+}
+
+func Test_selectDeploymentSpecs(t *testing.T) {
+	defer func() { flagSelector = "" }()
+
+	flagSelector = "cluster=east,name!~^s"
+	specs, err := selectDeploymentSpecs(testSpecs[:])
+	assert.NoError(t, err)
+	assert.Len(t, specs, 2)
+	assert.Equal(t, "crm", specs[0].Name())
+	assert.Equal(t, "erp", specs[1].Name())
+}
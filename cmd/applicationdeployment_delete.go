@@ -9,6 +9,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/skatteetaten/ao/pkg/client"
+	"github.com/skatteetaten/ao/pkg/deploymentspec"
 	"github.com/skatteetaten/ao/pkg/prompt"
 	"github.com/skatteetaten/ao/pkg/service"
 	"github.com/spf13/cobra"
@@ -44,6 +45,7 @@ func init() {
 	applicationDeploymentDeleteCmd.Flags().BoolVarP(&flagNoPrompt, "yes", "y", false, "Suppress prompts and accept deletion")
 	applicationDeploymentDeleteCmd.Flags().BoolVarP(&flagNoPrompt, "no-prompt", "", false, "Suppress prompts and accept deletion")
 	applicationDeploymentDeleteCmd.Flags().StringArrayVarP(&flagExcludes, "exclude", "e", []string{}, "Select applications or environments to exclude from deletion")
+	addSelectorFlag(applicationDeploymentDeleteCmd)
 
 	applicationDeploymentDeleteCmd.Flags().BoolVarP(&flagNoPrompt, "force", "f", false, "Suppress prompts")
 	applicationDeploymentDeleteCmd.Flags().MarkHidden("force")
@@ -57,7 +59,8 @@ func deleteApplicationDeployment(cmd *cobra.Command, args []string) error {
 		apiCluster = strings.TrimSpace(pFlagAPICluster)
 	}
 
-	if len(args) > 2 || len(args) < 1 {
+	search, ok := getApplicationSearch(args)
+	if !ok {
 		return cmd.Usage()
 	}
 
@@ -66,11 +69,6 @@ func deleteApplicationDeployment(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	search := args[0]
-	if len(args) == 2 {
-		search = fmt.Sprintf("%s/%s", args[0], args[1])
-	}
-
 	auroraConfigName := AOSession.AuroraConfig
 	if flagAuroraConfig != "" {
 		auroraConfigName = flagAuroraConfig
@@ -81,7 +79,7 @@ func deleteApplicationDeployment(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	applications, err := service.GetApplications(apiClient, search, flagExcludes)
+	applications, err := getApplications(apiClient, search, flagExcludes)
 	if err != nil {
 		return err
 	} else if len(applications) == 0 {
@@ -93,28 +91,38 @@ func deleteApplicationDeployment(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	deployInfos, err := getDeployedApplications(getApplicationDeploymentClient, filteredDeploymentSpecs, auroraConfigName, pFlagToken)
+	filteredDeploymentSpecs, err = selectDeploymentSpecs(filteredDeploymentSpecs)
 	if err != nil {
 		return err
-	} else if len(deployInfos) == 0 {
-		return errors.New("No applications to delete")
 	}
 
-	partitions, err := createDeploymentPartitions(auroraConfigName, pFlagToken, AOConfig.Clusters, deployInfos)
+	deployInfos, err := getDeployedApplications(getApplicationDeploymentClient, filteredDeploymentSpecs, auroraConfigName, pFlagToken)
 	if err != nil {
 		return err
+	} else if len(deployInfos) == 0 {
+		return errors.New("No applications to delete")
 	}
 
 	if !getDeleteConfirmation(flagNoPrompt, deployInfos, cmd.OutOrStdout()) {
 		return errors.New("No applications to delete")
 	}
 
+	return deleteDeployments(deployInfos, auroraConfigName, cmd.OutOrStdout())
+}
+
+// deleteDeployments deletes running application deployments and prints the results
+func deleteDeployments(deployInfos []DeploymentInfo, auroraConfigName string, out io.Writer) error {
+	partitions, err := createDeploymentPartitions(auroraConfigName, pFlagToken, AOConfig.Clusters, deployInfos)
+	if err != nil {
+		return err
+	}
+
 	fullResults, err := deleteFromReachableClusters(getApplicationDeploymentClient, partitions)
 	if err != nil {
 		return err
 	}
 
-	printFullDeleteResults(fullResults, cmd.OutOrStdout())
+	printFullDeleteResults(fullResults, out)
 
 	for _, result := range fullResults {
 		if !result.deleteResults.Success {
@@ -132,6 +140,10 @@ func validateDeleteParams() error {
 		}
 	}
 
+	if _, err := deploymentspec.ParseSelector(flagSelector); err != nil {
+		return err
+	}
+
 	return nil
 }
 
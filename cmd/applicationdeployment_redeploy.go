@@ -23,6 +23,7 @@ func init() {
 	applicationDeploymentRedeployCmd.Flags().BoolVarP(&flagNoPrompt, "yes", "y", false, "Suppress prompts and accept redeploy")
 	applicationDeploymentRedeployCmd.Flags().BoolVarP(&flagNoPrompt, "no-prompt", "", false, "Suppress prompts and accept redeploy")
 	applicationDeploymentRedeployCmd.Flags().StringArrayVarP(&flagExcludes, "exclude", "e", []string{}, "Select applications or environments to exclude from redeploy")
+	addSelectorFlag(applicationDeploymentRedeployCmd)
 
 	applicationDeploymentRedeployCmd.Flags().BoolVarP(&flagNoPrompt, "force", "f", false, "Suppress prompts")
 	applicationDeploymentRedeployCmd.Flags().MarkHidden("force")
@@ -36,7 +37,8 @@ func redeployApplicationDeployment(cmd *cobra.Command, args []string) error {
 		apiCluster = strings.TrimSpace(pFlagAPICluster)
 	}
 
-	if len(args) > 2 || len(args) < 1 {
+	search, ok := getApplicationSearch(args)
+	if !ok {
 		return cmd.Usage()
 	}
 
@@ -45,11 +47,6 @@ func redeployApplicationDeployment(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	search := args[0]
-	if len(args) == 2 {
-		search = fmt.Sprintf("%s/%s", args[0], args[1])
-	}
-
 	auroraConfigName := AOSession.AuroraConfig
 	if flagAuroraConfig != "" {
 		auroraConfigName = flagAuroraConfig
@@ -60,7 +57,7 @@ func redeployApplicationDeployment(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	applications, err := service.GetApplications(apiClient, search, flagExcludes)
+	applications, err := getApplications(apiClient, search, flagExcludes)
 	if err != nil {
 		return err
 	} else if len(applications) == 0 {
@@ -72,6 +69,11 @@ func redeployApplicationDeployment(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	filteredDeploymentSpecs, err = selectDeploymentSpecs(filteredDeploymentSpecs)
+	if err != nil {
+		return err
+	}
+
 	err = checkForDuplicateSpecs(filteredDeploymentSpecs)
 	if err != nil {
 		return err
@@ -125,6 +127,10 @@ func validateRedeployParams() error {
 		}
 	}
 
+	if _, err := deploymentspec.ParseSelector(flagSelector); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -3,12 +3,15 @@ package cmd
 import (
 	"fmt"
 	"io"
+	"os"
 	"sort"
 	"strings"
 	"text/tabwriter"
 
 	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
 	"github.com/skatteetaten/ao/pkg/client"
+	"github.com/skatteetaten/ao/pkg/versioncontrol"
 )
 
 // DefaultTablePrinter prints a table on screen
@@ -82,3 +85,31 @@ func getAPIClient(auroraConfig, overrideToken, overrideCluster string) (*client.
 
 	return api, nil
 }
+
+// loadLocalAuroraConfig loads the AuroraConfig in the git checkout containing the working directory
+func loadLocalAuroraConfig() (string, *auroraconfig.AuroraConfig, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", nil, err
+	}
+	gitRoot, err := versioncontrol.FindGitPath(wd)
+	if err != nil {
+		return "", nil, err
+	}
+	ac, err := versioncontrol.CollectAuroraConfigFilesInRepo(DefaultAPIClient.Affiliation, gitRoot)
+	if err != nil {
+		return "", nil, err
+	}
+	return gitRoot, ac, nil
+}
+
+// findApplicationDeploymentRef finds the single ApplicationDeploymentRef matching search
+func findApplicationDeploymentRef(search string, fileNames auroraconfig.FileNames) (string, error) {
+	matches := auroraconfig.FindMatches(search, fileNames.GetApplicationDeploymentRefs(), false)
+	if len(matches) == 0 {
+		return "", errors.Errorf("No matches for %s", search)
+	} else if len(matches) > 1 {
+		return "", errors.Errorf("Search matched more than one file. Search must be more specific.\n%v", matches)
+	}
+	return matches[0], nil
+}
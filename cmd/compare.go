@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/deploymentspec"
+	"github.com/spf13/cobra"
+)
+
+const compareLong = `Compare the deploy specs of two applications, field by field.
+With --env, every application that exists in both environments is compared.
+Use --ignore-expected to skip fields that normally differ between environments
+(applicationDeploymentRef, cluster, envName, env and namespace), or --ignore to skip other fields.`
+
+const compareExample = `  # Compare an application in test and prod
+  ao compare test/app prod/app
+
+  # Compare all applications in test and prod, ignoring fields that are expected to differ
+  ao compare --env test prod --ignore-expected
+
+  # Compare without defaults, ignoring the version
+  ao compare test/app prod/app --no-defaults --ignore /version`
+
+var (
+	flagCompareEnv            bool
+	flagCompareIgnore         []string
+	flagCompareIgnoreExpected bool
+	flagCompareNoDefaults     bool
+	flagCompareJSON           bool
+)
+
+var compareCmd = &cobra.Command{
+	Use:         "compare <applicationDeploymentRef> <applicationDeploymentRef> | --env <environment> <environment>",
+	Short:       "Compare the deploy specs of applications or environments",
+	Long:        compareLong,
+	Annotations: map[string]string{"type": "remote"},
+	Example:     compareExample,
+	RunE:        Compare,
+}
+
+// Comparison is the differences between the deploy specs of two applications
+type Comparison struct {
+	Left        string                      `json:"left"`
+	Right       string                      `json:"right"`
+	Differences []deploymentspec.Difference `json:"differences"`
+}
+
+func init() {
+	RootCmd.AddCommand(compareCmd)
+	compareCmd.Flags().BoolVar(&flagCompareEnv, "env", false, "compare all applications existing in both environments")
+	compareCmd.Flags().StringSliceVar(&flagCompareIgnore, "ignore", []string{}, "fields to ignore, e.g. /version,/config")
+	compareCmd.Flags().BoolVar(&flagCompareIgnoreExpected, "ignore-expected", false, "ignore fields that normally differ between environments")
+	compareCmd.Flags().BoolVar(&flagCompareNoDefaults, "no-defaults", false, "exclude default values")
+	compareCmd.Flags().BoolVar(&flagCompareJSON, "json", false, "print the differences as json")
+}
+
+// Compare is the entry point of the `compare` cli command
+func Compare(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return cmd.Usage()
+	}
+
+	fileNames, err := DefaultAPIClient.GetFileNames()
+	if err != nil {
+		return err
+	}
+
+	var pairs [][2]string
+	var unmatched []string
+	if flagCompareEnv {
+		pairs, unmatched = pairEnvironments(args[0], args[1], fileNames.GetApplicationDeploymentRefs())
+		if len(pairs) == 0 {
+			return errors.Errorf("No applications exist in both %s and %s", args[0], args[1])
+		}
+	} else {
+		left, err := findApplicationDeploymentRef(args[0], fileNames)
+		if err != nil {
+			return err
+		}
+		right, err := findApplicationDeploymentRef(args[1], fileNames)
+		if err != nil {
+			return err
+		}
+		pairs = [][2]string{{left, right}}
+	}
+
+	var refs []string
+	for _, pair := range pairs {
+		refs = append(refs, pair[0], pair[1])
+	}
+	specs, err := DefaultAPIClient.GetAuroraDeploySpec(refs, !flagCompareNoDefaults, false)
+	if err != nil {
+		return err
+	}
+	specsByRef := make(map[string]deploymentspec.DeploymentSpec)
+	for _, spec := range specs {
+		specsByRef[spec.GetString("applicationDeploymentRef")] = spec
+	}
+
+	ignore := append([]string{}, flagCompareIgnore...)
+	if flagCompareIgnoreExpected {
+		ignore = append(ignore, deploymentspec.ExpectedDifferences...)
+	}
+
+	var comparisons []Comparison
+	for _, pair := range pairs {
+		left, right := specsByRef[pair[0]], specsByRef[pair[1]]
+		if left == nil || right == nil {
+			return errors.Errorf("Could not get deploy specs for %s and %s", pair[0], pair[1])
+		}
+		differences, err := deploymentspec.Compare(left, right, ignore)
+		if err != nil {
+			return errors.Wrapf(err, "Could not compare %s and %s", pair[0], pair[1])
+		}
+		comparisons = append(comparisons, Comparison{
+			Left:        pair[0],
+			Right:       pair[1],
+			Differences: differences,
+		})
+	}
+
+	if flagCompareJSON {
+		data, err := json.MarshalIndent(comparisons, "", "  ")
+		if err != nil {
+			return err
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+
+	for i, comparison := range comparisons {
+		if i > 0 {
+			cmd.Println()
+		}
+		if len(comparison.Differences) == 0 {
+			cmd.Printf("%s and %s are equal\n", comparison.Left, comparison.Right)
+			continue
+		}
+		header, rows := getComparisonTable(comparison)
+		DefaultTablePrinter(header, rows, cmd.OutOrStdout())
+	}
+	if len(unmatched) > 0 {
+		cmd.Printf("\nOnly in one environment: %s\n", strings.Join(unmatched, ", "))
+	}
+	return nil
+}
+
+// pairEnvironments pairs the applications existing in both environments, and returns the rest as unmatched
+func pairEnvironments(leftEnv, rightEnv string, applicationDeploymentRefs []string) ([][2]string, []string) {
+	apps := make(map[string]map[string]bool)
+	for _, ref := range applicationDeploymentRefs {
+		parts := strings.Split(ref, "/")
+		if len(parts) != 2 || (parts[0] != leftEnv && parts[0] != rightEnv) {
+			continue
+		}
+		if apps[parts[1]] == nil {
+			apps[parts[1]] = make(map[string]bool)
+		}
+		apps[parts[1]][parts[0]] = true
+	}
+
+	var names []string
+	for name := range apps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var pairs [][2]string
+	var unmatched []string
+	for _, name := range names {
+		switch {
+		case apps[name][leftEnv] && apps[name][rightEnv]:
+			pairs = append(pairs, [2]string{leftEnv + "/" + name, rightEnv + "/" + name})
+		case apps[name][leftEnv]:
+			unmatched = append(unmatched, leftEnv+"/"+name)
+		default:
+			unmatched = append(unmatched, rightEnv+"/"+name)
+		}
+	}
+	return pairs, unmatched
+}
+
+func getComparisonTable(comparison Comparison) (string, []string) {
+	var rows []string
+	for _, difference := range comparison.Differences {
+		rows = append(rows, fmt.Sprintf("%s\t%s\t%s", difference.Path, formatComparedValue(difference.Left), formatComparedValue(difference.Right)))
+	}
+	return fmt.Sprintf("FIELD\t%s\t%s", strings.ToUpper(comparison.Left), strings.ToUpper(comparison.Right)), rows
+}
+
+func formatComparedValue(value interface{}) string {
+	if value == nil {
+		return "-"
+	}
+	return formatExplainedValue(value)
+}
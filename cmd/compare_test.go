@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/skatteetaten/ao/pkg/deploymentspec"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_pairEnvironments(t *testing.T) {
+	refs := []string{"test/foo", "test/bar", "prod/foo", "prod/baz", "dev/foo", "about"}
+
+	pairs, unmatched := pairEnvironments("test", "prod", refs)
+
+	assert.Equal(t, [][2]string{{"test/foo", "prod/foo"}}, pairs)
+	assert.Equal(t, []string{"test/bar", "prod/baz"}, unmatched)
+}
+
+func Test_getComparisonTable(t *testing.T) {
+	comparison := Comparison{
+		Left:  "test/foo",
+		Right: "prod/foo",
+		Differences: []deploymentspec.Difference{
+			{Path: "/version", Left: "1.0", Right: "1.1"},
+			{Path: "/route", Left: true, Right: nil},
+		},
+	}
+
+	header, rows := getComparisonTable(comparison)
+
+	assert.Equal(t, "FIELD\tTEST/FOO\tPROD/FOO", header)
+	assert.Equal(t, []string{"/version\t\"1.0\"\t\"1.1\"", "/route\ttrue\t-"}, rows)
+}
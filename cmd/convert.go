@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+	"github.com/skatteetaten/ao/pkg/versioncontrol"
+	"github.com/spf13/cobra"
+)
+
+const convertLong = `Convert AuroraConfig files between JSON and YAML.
+The converted file replaces the original file. Files referring to a converted file
+with baseFile or envFile must be updated as well, use --update-references to do so.
+Comments in YAML files are lost when converting to JSON.`
+
+const convertExample = `  # Convert test/foo.json to test/foo.yaml
+  ao convert test/foo --to yaml
+
+  # Convert all files in the test environment to yaml in the local checkout
+  ao convert test --to yaml --local
+
+  # Convert foo.yaml back to json, and update baseFile in files referring to it
+  ao convert foo.yaml --to json --update-references`
+
+var (
+	flagConvertTo        string
+	flagConvertLocal     bool
+	flagUpdateReferences bool
+)
+
+var convertCmd = &cobra.Command{
+	Use:         "convert <file-selector>",
+	Short:       "Convert AuroraConfig files between JSON and YAML",
+	Long:        convertLong,
+	Annotations: map[string]string{"type": "remote"},
+	Example:     convertExample,
+	RunE:        Convert,
+}
+
+func init() {
+	RootCmd.AddCommand(convertCmd)
+	convertCmd.Flags().StringVar(&flagConvertTo, "to", "", "Format to convert to: json|yaml")
+	convertCmd.Flags().BoolVar(&flagConvertLocal, "local", false, "Convert files in the local checkout instead of the remote AuroraConfig")
+	convertCmd.Flags().BoolVar(&flagUpdateReferences, "update-references", false, "Update baseFile and envFile in files referring to converted files")
+	convertCmd.MarkFlagRequired("to")
+}
+
+// Convert is the entry point of the `convert` cli command
+func Convert(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return cmd.Usage()
+	}
+	selector := args[0]
+
+	var gitRoot string
+	var ac *auroraconfig.AuroraConfig
+	var err error
+	if flagConvertLocal {
+		gitRoot, ac, err = loadLocalAuroraConfig()
+	} else {
+		ac, err = DefaultAPIClient.GetAuroraConfig()
+	}
+	if err != nil {
+		return err
+	}
+
+	selected := ac.FileNames().Select(selector)
+	if len(selected) == 0 {
+		return errors.Errorf("No files matching %s", selector)
+	}
+
+	loaded := ac.Copy()
+	result, err := ac.ConvertFiles(selected, flagConvertTo, flagUpdateReferences)
+	if err != nil {
+		return err
+	}
+	if len(result.Renamed) == 0 {
+		cmd.Printf("Nothing to convert, all files matching %s are %s\n", selector, flagConvertTo)
+		return nil
+	}
+
+	if flagConvertLocal {
+		if err := saveConvertedFilesInRepo(gitRoot, ac, result); err != nil {
+			return err
+		}
+	} else {
+		if err := DefaultAPIClient.SaveAuroraConfig(loaded, ac); err != nil {
+			return err
+		}
+	}
+
+	var oldNames []string
+	for oldName := range result.Renamed {
+		oldNames = append(oldNames, oldName)
+	}
+	sort.Strings(oldNames)
+	for _, oldName := range oldNames {
+		cmd.Printf("%s has been converted to %s\n", oldName, result.Renamed[oldName])
+	}
+	for _, reference := range result.References {
+		cmd.Printf("%s in %s has been updated\n", reference.Key, currentName(result, reference.File))
+	}
+
+	return nil
+}
+
+func saveConvertedFilesInRepo(gitRoot string, ac *auroraconfig.AuroraConfig, result *auroraconfig.ConvertResult) error {
+	var changed []auroraconfig.File
+	var removed []string
+	for oldName, newName := range result.Renamed {
+		changed = append(changed, *ac.GetFile(newName))
+		removed = append(removed, oldName)
+	}
+	for _, reference := range result.References {
+		changed = append(changed, *ac.GetFile(currentName(result, reference.File)))
+	}
+
+	if err := versioncontrol.WriteAuroraConfigFilesInRepo(gitRoot, changed); err != nil {
+		return err
+	}
+	return versioncontrol.RemoveAuroraConfigFilesInRepo(gitRoot, removed)
+}
+
+// currentName returns the name of a file after conversion
+func currentName(result *auroraconfig.ConvertResult, fileName string) string {
+	if newName, ok := result.Renamed[fileName]; ok {
+		return newName
+	}
+	return fileName
+}
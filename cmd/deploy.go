@@ -45,6 +45,12 @@ const exampleDeploy = `  Given the following AuroraConfig:
 
   # Exclude environment(s) when deploying an application across environments (regexp)
   ao deploy bar -e ref/.*
+
+  # Select applications by their deploy spec: deploy all applications in cluster utv04 except snapshots
+  ao deploy --selector 'cluster=utv04,version!~SNAPSHOT'
+
+  # Deploy the applications in the group payments, see 'ao group'
+  ao deploy @payments
 `
 
 var deployCmd = &cobra.Command{
@@ -67,6 +73,7 @@ func init() {
 	deployCmd.Flags().StringArrayVarP(&flagOverrides, "overrides", "o", []string{}, "Override in the form '[env/]file:{<json override>}'")
 	deployCmd.Flags().StringArrayVarP(&flagExcludes, "exclude", "e", []string{}, "Select applications or environments to exclude from deploy")
 	deployCmd.Flags().StringVarP(&flagVersion, "version", "v", "", "Set the given version in AuroraConfig before deploy")
+	addSelectorFlag(deployCmd)
 
 	deployCmd.Flags().BoolVarP(&flagNoPrompt, "force", "f", false, "Suppress prompts and accept deployment(s)")
 	deployCmd.Flags().MarkHidden("force")
@@ -80,7 +87,8 @@ func deploy(cmd *cobra.Command, args []string) error {
 		apiCluster = strings.TrimSpace(pFlagAPICluster)
 	}
 
-	if len(args) > 2 || len(args) < 1 {
+	search, ok := getApplicationSearch(args)
+	if !ok {
 		return cmd.Usage()
 	}
 
@@ -89,11 +97,6 @@ func deploy(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	search := args[0]
-	if len(args) == 2 {
-		search = fmt.Sprintf("%s/%s", args[0], args[1])
-	}
-
 	auroraConfigName := AOSession.AuroraConfig
 	if flagAuroraConfig != "" {
 		auroraConfigName = flagAuroraConfig
@@ -104,23 +107,36 @@ func deploy(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	applications, err := service.GetApplications(apiClient, search, flagExcludes)
+	applications, err := getApplications(apiClient, search, flagExcludes)
 	if err != nil {
 		return err
 	} else if len(applications) == 0 {
 		return errors.New("No applications to deploy")
 	}
 
-	flagVersion = strings.TrimSpace(flagVersion) // trimming nbsp
-	if flagVersion != "" && len(applications) > 1 {
-		return errors.New("Deploy with version does only support one application")
-	}
-
 	filteredDeploymentSpecs, err := service.GetFilteredDeploymentSpecs(apiClient, applications, flagCluster)
 	if err != nil {
 		return err
 	}
 
+	if flagSelector != "" {
+		filteredDeploymentSpecs, err = selectDeploymentSpecs(filteredDeploymentSpecs)
+		if err != nil {
+			return err
+		} else if len(filteredDeploymentSpecs) == 0 {
+			return errors.New("No applications to deploy")
+		}
+		applications = nil
+		for _, spec := range filteredDeploymentSpecs {
+			applications = append(applications, spec.GetString("applicationDeploymentRef"))
+		}
+	}
+
+	flagVersion = strings.TrimSpace(flagVersion) // trimming nbsp
+	if flagVersion != "" && len(applications) > 1 {
+		return errors.New("Deploy with version does only support one application")
+	}
+
 	overrideConfig, err := parseOverride(flagOverrides)
 	if err != nil {
 		return err
@@ -182,6 +198,10 @@ func validateParams() error {
 		}
 	}
 
+	if _, err := deploymentspec.ParseSelector(flagSelector); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -400,3 +420,22 @@ func updateVersion(apiClient client.AuroraConfigClient, applications []string, v
 
 	return nil
 }
+
+// deployApplications deploys the given ApplicationDeploymentRefs after confirmation, and prints the results
+func deployApplications(cmd *cobra.Command, applications []string, force bool) error {
+	specs, err := service.GetFilteredDeploymentSpecs(DefaultAPIClient, applications, "")
+	if err != nil {
+		return err
+	}
+	partitions, err := createDeploySpecPartitions(AOSession.AuroraConfig, pFlagToken, AOConfig.Clusters, specs)
+	if err != nil {
+		return err
+	}
+	if !getDeployConfirmation(force, specs, "", cmd.OutOrStdout(), "") {
+		return errors.New("Did not deploy any applications")
+	}
+
+	result, unsuccessfulErr := deployToReachableClusters(getApplicationDeploymentClient, partitions, map[string]string{})
+	printDeployResult(result, cmd.OutOrStdout())
+	return unsuccessfulErr
+}
@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/skatteetaten/ao/pkg/auroraconfig"
@@ -9,7 +11,15 @@ import (
 	"github.com/spf13/cobra"
 )
 
-const editLong = `Edit a single file in the current AuroraConfig.`
+const editLong = `Edit one or more files in the current AuroraConfig.
+Each argument selects files by name, environment folder, glob pattern or fuzzy match.
+Several files are opened in one editor buffer, each below a '## File: <name>' header,
+and only the changed files are saved.
+Before saving, the whole AuroraConfig is validated with your changes, and the editor is reopened
+with the errors caused by your changes. Errors that are in the AuroraConfig without your changes do
+not stop the save. If someone else saves a file while you are editing it, your changes are merged
+with theirs. A clean merge is validated again and saved right away. If the merge has conflicts,
+the editor is reopened with conflict markers around your changes (mine) and theirs.`
 
 const exampleEdit = `  Given the following AuroraConfig:
     - about.json
@@ -24,11 +34,14 @@ const exampleEdit = `  Given the following AuroraConfig:
 
   # Fuzzy matching: will open foo/foobar.json in editor
   ao edit fofoba
+
+  # Will open about.json, foo/bar.json and foo/foobar.json in one editor
+  ao edit about.json 'foo/*bar'
 `
 
 var editCmd = &cobra.Command{
-	Use:         "edit [env/]file",
-	Short:       "Edit a single file in the AuroraConfig repository",
+	Use:         "edit [env/]file...",
+	Short:       "Edit one or more files in the AuroraConfig repository",
 	Long:        editLong,
 	Annotations: map[string]string{"type": "remote"},
 	Example:     exampleEdit,
@@ -50,33 +63,29 @@ func EditFile(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	search := args[0]
-	if len(args) == 2 {
-		search = fmt.Sprintf("%s/%s", args[0], args[1])
+	selected, err := selectEditFiles(args, fileNames)
+	if err != nil {
+		return err
+	}
+
+	ac, err := DefaultAPIClient.GetAuroraConfig()
+	if err != nil {
+		return err
 	}
 
-	matches := auroraconfig.FindMatches(search, fileNames, true)
-	if len(matches) == 0 {
-		return errors.Errorf("No matches for %s", search)
-	} else if len(matches) > 1 {
-		return errors.Errorf("Search matched more than one file. Search must be more specific.\n%v", matches)
+	if len(selected) > 1 {
+		return editFiles(cmd, ac, selected)
 	}
 
-	fileName := matches[0]
+	fileName := selected[0]
 	file, eTag, err := DefaultAPIClient.GetAuroraConfigFile(fileName)
 	if err != nil {
 		return err
 	}
 
-	fileEditor := editor.NewEditor(func(modified string) error {
-		file.Contents = modified
-
-		// Save config file (Gobo)
-		if err = DefaultAPIClient.UpdateAuroraConfigFile(file, eTag); err != nil {
-			return err
-		}
-		return nil
-	})
+	validator := newEditValidator(DefaultAPIClient, ac, cmd.OutOrStdout())
+	save := newMergingSaveFunc(DefaultAPIClient, file, eTag, validator.validateFile(fileName), cmd.OutOrStdout())
+	fileEditor := editor.NewEditor(newValidatingSaveFunc(validator, fileName, save))
 
 	err = fileEditor.Edit(string(file.Contents), file.Name)
 	if err != nil {
@@ -86,3 +95,261 @@ func EditFile(cmd *cobra.Command, args []string) error {
 	fmt.Println(fileName, "edited")
 	return nil
 }
+
+func editFiles(cmd *cobra.Command, ac *auroraconfig.AuroraConfig, fileNames []string) error {
+	validator := newEditValidator(DefaultAPIClient, ac, cmd.OutOrStdout())
+	var files []editor.File
+	saves := make(map[string]editor.OnSaveFunc)
+	for _, fileName := range fileNames {
+		file, eTag, err := DefaultAPIClient.GetAuroraConfigFile(fileName)
+		if err != nil {
+			return err
+		}
+		files = append(files, editor.File{Name: fileName, Content: file.Contents})
+		saves[fileName] = newMergingSaveFunc(DefaultAPIClient, file, eTag, validator.validateFile(fileName), cmd.OutOrStdout())
+	}
+
+	fileEditor := editor.NewEditor(newMultiFileSaveFunc(validator, files, saves, cmd.OutOrStdout()))
+	return fileEditor.EditFiles(files)
+}
+
+// selectEditFiles returns the files selected by args. Each arg is a file name, folder or glob pattern,
+// or else a fuzzy search matching a single file. For backwards compatibility, `edit env file` selects env/file.
+func selectEditFiles(args []string, fileNames auroraconfig.FileNames) ([]string, error) {
+	if len(args) == 2 && !strings.Contains(args[0], auroraconfig.Separator) && !strings.Contains(args[1], auroraconfig.Separator) {
+		for _, environment := range fileNames.GetEnvironments() {
+			if environment == args[0] {
+				args = []string{args[0] + auroraconfig.Separator + args[1]}
+				break
+			}
+		}
+	}
+
+	var selected []string
+	seen := make(map[string]bool)
+	for _, search := range args {
+		matches := fileNames.Select(search)
+		if len(matches) == 0 {
+			matches = auroraconfig.FindMatches(search, fileNames, true)
+			if len(matches) == 0 {
+				return nil, errors.Errorf("No matches for %s", search)
+			} else if len(matches) > 1 {
+				return nil, errors.Errorf("Search matched more than one file. Search must be more specific.\n%v", matches)
+			}
+		}
+		for _, match := range matches {
+			if !seen[match] {
+				seen[match] = true
+				selected = append(selected, match)
+			}
+		}
+	}
+	return selected, nil
+}
+
+// AuroraConfigFileClient gets and updates single AuroraConfig files
+type AuroraConfigFileClient interface {
+	GetAuroraConfigFile(fileName string) (*auroraconfig.File, string, error)
+	UpdateAuroraConfigFile(file *auroraconfig.File, eTag string) error
+}
+
+// AuroraConfigValidator validates a complete AuroraConfig
+type AuroraConfigValidator interface {
+	ValidateAuroraConfig(ac *auroraconfig.AuroraConfig, fullValidation bool) (string, error)
+}
+
+// editValidator validates an AuroraConfig with edited files swapped in. Only errors that are not in the
+// AuroraConfig without the edits stop a save, so that an application that is already invalid does not stop every edit.
+type editValidator struct {
+	validator AuroraConfigValidator
+	ac        *auroraconfig.AuroraConfig
+	out       io.Writer
+	// baseline holds the errors of the AuroraConfig before any edits, and is nil until it is validated
+	baseline map[string]bool
+}
+
+func newEditValidator(validator AuroraConfigValidator, ac *auroraconfig.AuroraConfig, out io.Writer) *editValidator {
+	return &editValidator{validator: validator, ac: ac, out: out}
+}
+
+// validateFile returns a function validating the edited contents of fileName
+func (v *editValidator) validateFile(fileName string) func(modified string) error {
+	return func(modified string) error {
+		return v.validate(map[string]string{fileName: modified})
+	}
+}
+
+// validate validates the AuroraConfig with the changed contents of files swapped in
+func (v *editValidator) validate(changes map[string]string) error {
+	if v.baseline == nil {
+		v.baseline = make(map[string]bool)
+		if _, err := v.validator.ValidateAuroraConfig(v.ac, false); err != nil {
+			for _, validationError := range splitValidationErrors(err.Error()) {
+				v.baseline[validationError] = true
+			}
+		}
+	}
+
+	for fileName, contents := range changes {
+		file := v.ac.GetFile(fileName)
+		if file == nil {
+			return errors.Errorf("could not find %s in AuroraConfig", fileName)
+		}
+		file.Contents = contents
+	}
+
+	warnings, err := v.validator.ValidateAuroraConfig(v.ac, false)
+	if err != nil {
+		var introduced []string
+		existing := 0
+		for _, validationError := range splitValidationErrors(err.Error()) {
+			if v.baseline[validationError] {
+				existing++
+			} else {
+				introduced = append(introduced, validationError)
+			}
+		}
+		if len(introduced) > 0 {
+			return errors.Errorf("The AuroraConfig is not valid with your changes:\n%s", strings.Join(introduced, "\n\n"))
+		}
+		fmt.Fprintf(v.out, "Ignoring %d error(s) that are in the AuroraConfig without your changes\n", existing)
+	}
+	if warnings != "" {
+		fmt.Fprintln(v.out, warnings)
+	}
+	return nil
+}
+
+// splitValidationErrors splits the errors from validating an AuroraConfig into one error per application and field.
+// Each error starts with an Application line, and any lines before the first error are left out.
+func splitValidationErrors(message string) []string {
+	var validationErrors []string
+	for _, block := range strings.Split(message, "\n\n") {
+		if index := strings.Index(block, "Application: "); index > 0 {
+			block = block[index:]
+		}
+		if block = strings.TrimSpace(block); block != "" {
+			validationErrors = append(validationErrors, block)
+		}
+	}
+	return validationErrors
+}
+
+// newValidatingSaveFunc validates the edited contents of fileName before calling save
+func newValidatingSaveFunc(validator *editValidator, fileName string, save editor.OnSaveFunc) editor.OnSaveFunc {
+	return func(modified string) error {
+		if err := validator.validateFile(fileName)(modified); err != nil {
+			return err
+		}
+		return save(modified)
+	}
+}
+
+// newMultiFileSaveFunc saves the changed files in a buffer of several files, each with its own save function.
+// If any file fails, the editor is reopened with the errors, and the files that were saved are not saved again.
+func newMultiFileSaveFunc(validator *editValidator, files []editor.File, saves map[string]editor.OnSaveFunc, out io.Writer) editor.OnSaveFunc {
+	saved := make(map[string]string)
+	for _, file := range files {
+		saved[file.Name] = strings.TrimRight(file.Content, "\n")
+	}
+
+	return func(modified string) error {
+		edited, err := editor.SplitFiles(modified)
+		if err != nil {
+			return err
+		}
+		if len(edited) != len(files) {
+			return errors.Errorf("Expected %d files, the '%s' lines must not be changed", len(files), editor.FileHeader)
+		}
+		changes := make(map[string]string)
+		for i, file := range edited {
+			if file.Name != files[i].Name {
+				return errors.Errorf("Expected %s, the '%s' lines must not be changed", files[i].Name, editor.FileHeader)
+			}
+			if file.Content != saved[file.Name] {
+				changes[file.Name] = file.Content
+			}
+		}
+		if len(changes) == 0 {
+			return nil
+		}
+		if err := validator.validate(changes); err != nil {
+			return err
+		}
+
+		var failures []string
+		for i, file := range edited {
+			if _, changed := changes[file.Name]; !changed {
+				continue
+			}
+			err := saves[file.Name](file.Content)
+			var replaceContent *editor.ReplaceContentError
+			switch {
+			case err == nil:
+				saved[file.Name] = file.Content
+				fmt.Fprintf(out, "%s edited\n", file.Name)
+			case errors.As(err, &replaceContent):
+				edited[i].Content = replaceContent.Content
+				failures = append(failures, file.Name+": "+replaceContent.Message)
+			default:
+				failures = append(failures, file.Name+": "+err.Error())
+			}
+		}
+		if len(failures) > 0 {
+			return &editor.ReplaceContentError{Message: strings.Join(failures, "\n"), Content: editor.JoinFiles(edited)}
+		}
+		return nil
+	}
+}
+
+// newMergingSaveFunc saves edits of file. If the file has been changed remotely since it was fetched,
+// the edits are merged with the remote changes, and the merge is saved if it has no conflicts and passes validate.
+func newMergingSaveFunc(fileClient AuroraConfigFileClient, file *auroraconfig.File, eTag string, validate func(modified string) error, out io.Writer) editor.OnSaveFunc {
+	base := *file
+	return func(modified string) error {
+		mine := base
+		mine.Contents = modified
+
+		// Save config file (Gobo)
+		err := fileClient.UpdateAuroraConfigFile(&mine, eTag)
+		if err == nil {
+			return nil
+		}
+
+		theirs, theirsETag, getErr := fileClient.GetAuroraConfigFile(file.Name)
+		if getErr != nil || theirsETag == eTag {
+			return err
+		}
+		previous := base
+		base, eTag = *theirs, theirsETag
+
+		merged, conflicts, mergeErr := auroraconfig.Merge(&previous, &mine, theirs)
+		if mergeErr != nil || len(conflicts) > 0 {
+			content, _ := auroraconfig.MergeWithMarkers(previous.Contents, modified, theirs.Contents)
+			return &editor.ReplaceContentError{
+				Message: getMergeConflictMessage(file.Name, conflicts, mergeErr),
+				Content: content,
+			}
+		}
+
+		fmt.Fprintf(out, "%s has been changed since edit, your changes have been merged with theirs\n", file.Name)
+		if err := validate(merged.Contents); err != nil {
+			return &editor.ReplaceContentError{Message: err.Error(), Content: merged.Contents}
+		}
+		if err := fileClient.UpdateAuroraConfigFile(merged, eTag); err != nil {
+			return &editor.ReplaceContentError{Message: err.Error(), Content: merged.Contents}
+		}
+		return nil
+	}
+}
+
+func getMergeConflictMessage(fileName string, conflicts []auroraconfig.MergeConflict, mergeErr error) string {
+	message := fmt.Sprintf("%s has been changed since edit, and your changes could not be merged with theirs.\n", fileName)
+	if mergeErr != nil {
+		message += mergeErr.Error() + "\n"
+	}
+	for _, conflict := range conflicts {
+		message += fmt.Sprintf("Conflict in %s\n", conflict.Path)
+	}
+	return message + fmt.Sprintf("Resolve the conflicts marked with %s and %s", auroraconfig.MarkerMine, auroraconfig.MarkerTheirs)
+}
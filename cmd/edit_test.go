@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+	"github.com/skatteetaten/ao/pkg/editor"
+	"github.com/stretchr/testify/assert"
+)
+
+type fileClientStub struct {
+	file  auroraconfig.File
+	eTag  string
+	saved []string
+}
+
+func (c *fileClientStub) GetAuroraConfigFile(fileName string) (*auroraconfig.File, string, error) {
+	file := c.file
+	return &file, c.eTag, nil
+}
+
+func (c *fileClientStub) UpdateAuroraConfigFile(file *auroraconfig.File, eTag string) error {
+	if eTag != c.eTag {
+		return errors.New("File has changed since edit")
+	}
+	c.file.Contents = file.Contents
+	c.saved = append(c.saved, file.Contents)
+	return nil
+}
+
+func Test_newMergingSaveFunc(t *testing.T) {
+	base := auroraconfig.File{Name: "utv/foo.json", Contents: `{"version": "1", "replicas": 1}`}
+	fileClient := &fileClientStub{file: auroraconfig.File{Name: "utv/foo.json", Contents: `{"version": "1", "replicas": 2}`}, eTag: "theirs"}
+	out := &bytes.Buffer{}
+
+	var validated []string
+	validate := func(modified string) error {
+		validated = append(validated, modified)
+		return nil
+	}
+
+	save := newMergingSaveFunc(fileClient, &base, "base", validate, out)
+	err := save(`{"version": "2", "replicas": 1}`)
+	assert.NoError(t, err)
+	assert.Len(t, fileClient.saved, 1)
+	assert.JSONEq(t, `{"version": "2", "replicas": 2}`, fileClient.saved[0])
+	assert.Equal(t, fileClient.saved, validated)
+	assert.Equal(t, "utv/foo.json has been changed since edit, your changes have been merged with theirs\n", out.String())
+}
+
+func Test_newMergingSaveFuncWithInvalidMerge(t *testing.T) {
+	base := auroraconfig.File{Name: "utv/foo.json", Contents: `{"version": "1", "replicas": 1}`}
+	fileClient := &fileClientStub{file: auroraconfig.File{Name: "utv/foo.json", Contents: `{"version": "1", "replicas": 2}`}, eTag: "theirs"}
+	validate := func(modified string) error {
+		return errors.New("The AuroraConfig is not valid with your changes")
+	}
+
+	save := newMergingSaveFunc(fileClient, &base, "base", validate, &bytes.Buffer{})
+	err := save(`{"version": "2", "replicas": 1}`)
+	var replaceContent *editor.ReplaceContentError
+	assert.True(t, errors.As(err, &replaceContent))
+	assert.Equal(t, "The AuroraConfig is not valid with your changes", replaceContent.Message)
+	assert.JSONEq(t, `{"version": "2", "replicas": 2}`, replaceContent.Content)
+	assert.Empty(t, fileClient.saved)
+}
+
+func Test_newMergingSaveFuncWithConflicts(t *testing.T) {
+	base := auroraconfig.File{Name: "utv/foo.json", Contents: "{\n  \"version\": \"1\"\n}"}
+	fileClient := &fileClientStub{file: auroraconfig.File{Name: "utv/foo.json", Contents: "{\n  \"version\": \"3\"\n}"}, eTag: "theirs"}
+
+	save := newMergingSaveFunc(fileClient, &base, "base", func(string) error { return nil }, &bytes.Buffer{})
+	err := save("{\n  \"version\": \"2\"\n}")
+	var replaceContent *editor.ReplaceContentError
+	assert.True(t, errors.As(err, &replaceContent))
+	assert.Contains(t, replaceContent.Message, "Conflict in /version")
+	assert.Equal(t, "{\n<<<<<<< mine\n  \"version\": \"2\"\n=======\n  \"version\": \"3\"\n>>>>>>> theirs\n}\n", replaceContent.Content)
+	assert.Empty(t, fileClient.saved)
+
+	err = save("{\n  \"version\": \"4\"\n}")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"{\n  \"version\": \"4\"\n}"}, fileClient.saved)
+}
+
+type validatorStub struct {
+	validated []string
+}
+
+// ValidateAuroraConfig fails for utv/foo.json without a version, and for utv/bar.json if it exists
+func (v *validatorStub) ValidateAuroraConfig(ac *auroraconfig.AuroraConfig, fullValidation bool) (string, error) {
+	contents := ac.GetFile("utv/foo.json").Contents
+	v.validated = append(v.validated, contents)
+	var validationErrors []string
+	if ac.GetFile("utv/bar.json") != nil {
+		validationErrors = append(validationErrors, "Application: utv/bar\nField:       cluster (Missing)")
+	}
+	if contents == `{"version": ""}` {
+		validationErrors = append(validationErrors, "Application: utv/foo\nField:       version (Missing)")
+	}
+	if len(validationErrors) > 0 {
+		return "", errors.New("An error occurred for one or more applications\n" + strings.Join(validationErrors, "\n\n"))
+	}
+	return "", nil
+}
+
+func Test_newValidatingSaveFunc(t *testing.T) {
+	ac := &auroraconfig.AuroraConfig{Files: []auroraconfig.File{
+		{Name: "about.json", Contents: `{}`},
+		{Name: "utv/foo.json", Contents: `{"version": "1"}`},
+	}}
+	validator := &validatorStub{}
+	var saved []string
+	save := newValidatingSaveFunc(newEditValidator(validator, ac, &bytes.Buffer{}), "utv/foo.json", func(modified string) error {
+		saved = append(saved, modified)
+		return nil
+	})
+
+	err := save(`{"version": ""}`)
+	assert.EqualError(t, err, "The AuroraConfig is not valid with your changes:\nApplication: utv/foo\nField:       version (Missing)")
+	assert.Empty(t, saved)
+
+	err = save(`{"version": "2"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`{"version": "2"}`}, saved)
+	assert.Equal(t, []string{`{"version": "1"}`, `{"version": ""}`, `{"version": "2"}`}, validator.validated)
+}
+
+func Test_newValidatingSaveFuncWithExistingErrors(t *testing.T) {
+	ac := &auroraconfig.AuroraConfig{Files: []auroraconfig.File{
+		{Name: "about.json", Contents: `{}`},
+		{Name: "utv/bar.json", Contents: `{}`},
+		{Name: "utv/foo.json", Contents: `{"version": "1"}`},
+	}}
+	out := &bytes.Buffer{}
+	var saved []string
+	save := newValidatingSaveFunc(newEditValidator(&validatorStub{}, ac, out), "utv/foo.json", func(modified string) error {
+		saved = append(saved, modified)
+		return nil
+	})
+
+	err := save(`{"version": "2"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`{"version": "2"}`}, saved)
+	assert.Equal(t, "Ignoring 1 error(s) that are in the AuroraConfig without your changes\n", out.String())
+
+	err = save(`{"version": ""}`)
+	assert.EqualError(t, err, "The AuroraConfig is not valid with your changes:\nApplication: utv/foo\nField:       version (Missing)")
+	assert.Len(t, saved, 1)
+}
+
+func Test_selectEditFiles(t *testing.T) {
+	fileNames := auroraconfig.FileNames{"about.json", "foobar.json", "foo/about.json", "foo/bar.json", "foo/foobar.json"}
+
+	selected, err := selectEditFiles([]string{"foo", "bar"}, fileNames)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"foo/bar.json"}, selected)
+
+	selected, err = selectEditFiles([]string{"about", "foo/*bar", "foo/bar"}, fileNames)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"about.json", "foo/bar.json", "foo/foobar.json"}, selected)
+
+	selected, err = selectEditFiles([]string{"foo/foob"}, fileNames)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"foo/foobar.json"}, selected)
+
+	_, err = selectEditFiles([]string{"about", "prod/*"}, fileNames)
+	assert.EqualError(t, err, "No matches for prod/*")
+}
+
+func Test_newMultiFileSaveFunc(t *testing.T) {
+	ac := &auroraconfig.AuroraConfig{Files: []auroraconfig.File{
+		{Name: "about.json", Contents: `{}`},
+		{Name: "utv/foo.json", Contents: `{"version": "1"}`},
+	}}
+	files := []editor.File{{Name: "about.json", Content: `{}`}, {Name: "utv/foo.json", Content: `{"version": "1"}`}}
+	var saved []string
+	saves := map[string]editor.OnSaveFunc{
+		"about.json": func(modified string) error {
+			return errors.New("about.json should not be saved")
+		},
+		"utv/foo.json": func(modified string) error {
+			saved = append(saved, modified)
+			if len(saved) == 1 {
+				return &editor.ReplaceContentError{Message: "conflict", Content: "<<<<<<< mine"}
+			}
+			return nil
+		},
+	}
+	out := &bytes.Buffer{}
+	save := newMultiFileSaveFunc(newEditValidator(&validatorStub{}, ac, out), files, saves, out)
+
+	err := save("## File: about.json\n{}\n\n## File: utv/foo.json\n{\"version\": \"2\"}")
+	assert.EqualError(t, err, "utv/foo.json: conflict")
+	var replaceContent *editor.ReplaceContentError
+	assert.True(t, errors.As(err, &replaceContent))
+	assert.Equal(t, "## File: about.json\n{}\n\n## File: utv/foo.json\n<<<<<<< mine", replaceContent.Content)
+
+	err = save("## File: about.json\n{}\n\n## File: utv/foo.json\n{\"version\": \"3\"}")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`{"version": "2"}`, `{"version": "3"}`}, saved)
+	assert.Equal(t, "utv/foo.json edited\n", out.String())
+
+	err = save("## File: utv/foo.json\n{}")
+	assert.EqualError(t, err, "Expected 2 files, the '## File: ' lines must not be changed")
+}
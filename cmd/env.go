@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+	"github.com/skatteetaten/ao/pkg/prompt"
+	"github.com/skatteetaten/ao/pkg/service"
+	"github.com/spf13/cobra"
+)
+
+const envCloneLong = `Clone an environment by copying all files in its folder to a new environment folder.
+Each --set rewrites a value in the copied files that already contain the key. If none of them do,
+the value is set in the about file of the new environment. Values are read as JSON, such as 2, true
+or {"enabled":true}, and as text otherwise. Quote a value to keep it as text, such as '"2"'. The result is validated before any file is created.`
+
+const envDestroyLong = `Destroy an environment by deleting all files in its folder, and then the running
+deployments of its applications. No deployments are deleted if any of the files have been changed
+since they were fetched.`
+
+const envExample = `  # Clone test to utv05, running in the utv05 cluster, and deploy it
+  ao env clone test utv05 --set /cluster=utv05 --deploy
+
+  # Delete all deployments and files of utv05
+  ao env destroy utv05`
+
+var (
+	flagEnvSet    []string
+	flagEnvDeploy bool
+	flagEnvYes    bool
+)
+
+var (
+	envCmd = &cobra.Command{
+		Use:         "env",
+		Short:       "Clone or destroy environments",
+		Annotations: map[string]string{"type": "remote"},
+		Example:     envExample,
+	}
+
+	envCloneCmd = &cobra.Command{
+		Use:   "clone <source environment> <destination environment>",
+		Short: "Clone an environment into a new environment",
+		Long:  envCloneLong,
+		RunE:  CloneEnvironment,
+	}
+
+	envDestroyCmd = &cobra.Command{
+		Use:   "destroy <environment>",
+		Short: "Delete the running deployments and all files of an environment",
+		Long:  envDestroyLong,
+		RunE:  DestroyEnvironment,
+	}
+)
+
+func init() {
+	RootCmd.AddCommand(envCmd)
+	envCmd.AddCommand(envCloneCmd)
+	envCmd.AddCommand(envDestroyCmd)
+
+	envCloneCmd.Flags().StringArrayVar(&flagEnvSet, "set", []string{}, "value to rewrite in the form /path=value")
+	envCloneCmd.Flags().BoolVar(&flagEnvDeploy, "deploy", false, "deploy the new environment")
+	envCloneCmd.Flags().BoolVarP(&flagEnvYes, "yes", "y", false, "Suppress prompts and accept deployment")
+	envDestroyCmd.Flags().BoolVarP(&flagEnvYes, "yes", "y", false, "Suppress prompts and accept deletion")
+}
+
+// CloneEnvironment is the entry point of the `env clone` cli command
+func CloneEnvironment(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return cmd.Usage()
+	}
+	source, destination := args[0], args[1]
+
+	rewrites, err := parseRewrites(flagEnvSet)
+	if err != nil {
+		return err
+	}
+
+	ac, err := DefaultAPIClient.GetAuroraConfig()
+	if err != nil {
+		return err
+	}
+
+	cloned, err := ac.CloneEnvironment(source, destination)
+	if err != nil {
+		return err
+	}
+	for _, rewrite := range rewrites {
+		changed, err := auroraconfig.RewriteValue(cloned, rewrite.path, rewrite.value)
+		if err != nil {
+			return err
+		}
+		cmd.Printf("%s has been set to %s in %s\n", rewrite.path, rewrite.text, strings.Join(changed, ", "))
+	}
+
+	warnings, err := DefaultAPIClient.ValidateAuroraConfig(ac, false)
+	if err != nil {
+		return err
+	}
+	if warnings != "" {
+		cmd.Println(warnings)
+	}
+
+	for _, file := range cloned {
+		if err := DefaultAPIClient.CreateAuroraConfigFile(file); err != nil {
+			return err
+		}
+		cmd.Printf("%s has been created\n", file.Name)
+	}
+
+	if flagEnvDeploy {
+		applications := getEnvironmentApplications(ac.FileNames(), destination)
+		if len(applications) == 0 {
+			return errors.Errorf("No applications to deploy in %s", destination)
+		}
+		return deployApplications(cmd, applications, flagEnvYes)
+	}
+	return nil
+}
+
+// DestroyEnvironment is the entry point of the `env destroy` cli command
+func DestroyEnvironment(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return cmd.Usage()
+	}
+	environment := args[0]
+
+	ac, err := DefaultAPIClient.GetAuroraConfig()
+	if err != nil {
+		return err
+	}
+	fileNames := ac.FileNames()
+	files := fileNames.GetEnvironmentFiles(environment)
+	if len(files) == 0 {
+		return errors.Errorf("environment %s does not exist", environment)
+	}
+
+	applications := getEnvironmentApplications(fileNames, environment)
+	var deployInfos []DeploymentInfo
+	if len(applications) > 0 {
+		specs, err := service.GetFilteredDeploymentSpecs(DefaultAPIClient, applications, "")
+		if err != nil {
+			return err
+		}
+		deployInfos, err = getDeployedApplications(getApplicationDeploymentClient, specs, AOSession.AuroraConfig, pFlagToken)
+		if err != nil {
+			return err
+		}
+		if len(deployInfos) > 0 && !getDeleteConfirmation(flagEnvYes, deployInfos, cmd.OutOrStdout()) {
+			return errors.New("Did not destroy environment " + environment)
+		}
+	}
+
+	DefaultTablePrinter("FILES", files, cmd.OutOrStdout())
+	message := fmt.Sprintf("Do you want to delete %d file(s) in %s?", len(files), environment)
+	if !flagEnvYes && !prompt.Confirm(message, false) {
+		return errors.New("Did not delete any files")
+	}
+
+	// The files are deleted one by one with their ETags, and before the deployments, so that nothing is
+	// deleted from the cluster if any of the files have been changed remotely
+	loaded := ac.Copy()
+	for _, file := range files {
+		if err := ac.RemoveFile(file); err != nil {
+			return err
+		}
+	}
+	if err := DefaultAPIClient.SaveAuroraConfig(loaded, ac); err != nil {
+		return err
+	}
+	if len(deployInfos) > 0 {
+		if err := deleteDeployments(deployInfos, AOSession.AuroraConfig, cmd.OutOrStdout()); err != nil {
+			return err
+		}
+	}
+
+	cmd.Printf("Environment %s has been destroyed\n", environment)
+	return nil
+}
+
+// getEnvironmentApplications returns the ApplicationDeploymentRefs in an environment folder
+func getEnvironmentApplications(fileNames auroraconfig.FileNames, environment string) []string {
+	var applications []string
+	for _, application := range fileNames.GetApplicationDeploymentRefs() {
+		if strings.HasPrefix(application, environment+auroraconfig.Separator) {
+			applications = append(applications, application)
+		}
+	}
+	return applications
+}
+
+// rewrite is a value to set in a cloned environment
+type rewrite struct {
+	path  string
+	text  string
+	value interface{}
+}
+
+// parseRewrites parses rewrites in the form /path=value. The value is read as JSON, and as a string if it is not valid JSON.
+// Numbers that are not written the way JSON would write them, such as 1.0 or 01, are kept as strings.
+func parseRewrites(values []string) ([]rewrite, error) {
+	var rewrites []rewrite
+	for _, value := range values {
+		pathValue := strings.SplitN(value, "=", 2)
+		if len(pathValue) != 2 || !strings.HasPrefix(pathValue[0], "/") {
+			return nil, errors.Errorf("%s is not in the form /path=value", value)
+		}
+
+		var parsed interface{} = pathValue[1]
+		if jsonValue, err := auroraconfig.ParseValue(pathValue[1], auroraconfig.ValueTypeJSON); err == nil {
+			if number, ok := jsonValue.(float64); !ok || strconv.FormatFloat(number, 'f', -1, 64) == pathValue[1] {
+				parsed = jsonValue
+			}
+		}
+		rewrites = append(rewrites, rewrite{path: pathValue[0], text: pathValue[1], value: parsed})
+	}
+	return rewrites, nil
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_getEnvironmentApplications(t *testing.T) {
+	fileNames := auroraconfig.FileNames{"about.json", "foo.json", "utv/about.json", "utv/foo.yaml", "utv05/foo.json"}
+
+	assert.Equal(t, []string{"utv/foo"}, getEnvironmentApplications(fileNames, "utv"))
+	assert.Empty(t, getEnvironmentApplications(fileNames, "test"))
+}
+
+func Test_parseRewrites(t *testing.T) {
+	rewrites, err := parseRewrites([]string{
+		"/cluster=utv05",
+		"/config/URL=http://a?b=c",
+		"/replicas=2",
+		"/pause=true",
+		"/route={\"enabled\":true}",
+		"/version=1.0",
+		"/config/ID=\"2\"",
+		"/config/EMPTY=",
+	})
+	assert.NoError(t, err)
+
+	var values []interface{}
+	for _, rewrite := range rewrites {
+		values = append(values, rewrite.value)
+	}
+	assert.Equal(t, []interface{}{
+		"utv05",
+		"http://a?b=c",
+		float64(2),
+		true,
+		map[string]interface{}{"enabled": true},
+		"1.0",
+		"2",
+		"",
+	}, values)
+	assert.Equal(t, "/replicas", rewrites[2].path)
+	assert.Equal(t, "2", rewrites[2].text)
+
+	_, err = parseRewrites([]string{"cluster=utv05"})
+	assert.EqualError(t, err, "cluster=utv05 is not in the form /path=value")
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/deploymentspec"
+	"github.com/spf13/cobra"
+)
+
+const explainLong = `Explain where a value in the deployment spec of an application comes from.
+Shows the effective value and the file that set it, followed by every value it overrides from
+files with lower precedence and from defaults. When path is an object, every value below it is explained.`
+
+const explainExample = `  # Explain which file sets the host of route foo
+  ao explain test/app /route/foo/host
+
+  # Explain all resource settings, using the files in the local checkout
+  ao explain test/app /resources --local`
+
+var (
+	flagExplainLocal      bool
+	flagExplainNoDefaults bool
+	flagExplainJSON       bool
+)
+
+var explainCmd = &cobra.Command{
+	Use:         "explain <applicationDeploymentRef> <path>",
+	Short:       "Explain where a value in the deploy spec of an application comes from",
+	Long:        explainLong,
+	Annotations: map[string]string{"type": "remote"},
+	Example:     explainExample,
+	RunE:        Explain,
+}
+
+func init() {
+	RootCmd.AddCommand(explainCmd)
+	explainCmd.Flags().BoolVar(&flagExplainLocal, "local", false, "explain the configuration rendered from the files in the local checkout, without defaults")
+	explainCmd.Flags().BoolVar(&flagExplainNoDefaults, "no-defaults", false, "exclude default values")
+	explainCmd.Flags().BoolVar(&flagExplainJSON, "json", false, "print the explanation as json")
+}
+
+// Explain is the entry point of the `explain` cli command
+func Explain(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return cmd.Usage()
+	}
+
+	spec, err := getExplainedDeploySpec(args[0])
+	if err != nil {
+		return err
+	}
+
+	provenances, err := spec.Explain(args[1])
+	if err != nil {
+		return err
+	}
+
+	if flagExplainJSON {
+		data, err := json.MarshalIndent(provenances, "", "  ")
+		if err != nil {
+			return err
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 3, ' ', 0)
+	for _, row := range formatProvenances(provenances) {
+		fmt.Fprintln(w, row)
+	}
+	return w.Flush()
+}
+
+func getExplainedDeploySpec(search string) (deploymentspec.DeploymentSpec, error) {
+	if flagExplainLocal {
+		_, ac, err := loadLocalAuroraConfig()
+		if err != nil {
+			return nil, err
+		}
+		applicationDeploymentRef, err := findApplicationDeploymentRef(search, ac.FileNames())
+		if err != nil {
+			return nil, err
+		}
+		rendered, err := ac.RenderSpec(applicationDeploymentRef)
+		if err != nil {
+			return nil, err
+		}
+		return rendered.DeploymentSpec(), nil
+	}
+
+	fileNames, err := DefaultAPIClient.GetFileNames()
+	if err != nil {
+		return nil, err
+	}
+	applicationDeploymentRef, err := findApplicationDeploymentRef(search, fileNames)
+	if err != nil {
+		return nil, err
+	}
+	specs, err := DefaultAPIClient.GetAuroraDeploySpec([]string{applicationDeploymentRef}, !flagExplainNoDefaults, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(specs) != 1 {
+		return nil, errors.Errorf("Expected one deploy spec for %s, got %d", applicationDeploymentRef, len(specs))
+	}
+	return specs[0], nil
+}
+
+// formatProvenances formats the effective value of each path with its source, followed by the overridden values
+func formatProvenances(provenances []deploymentspec.Provenance) []string {
+	var lines []string
+	for _, provenance := range provenances {
+		lines = append(lines, fmt.Sprintf("%s = %s\t%s", provenance.Path, formatExplainedValue(provenance.Value), provenance.Source))
+		for _, source := range provenance.Overridden() {
+			lines = append(lines, fmt.Sprintf("  overrides %s\t%s", formatExplainedValue(source.Value), source.Name))
+		}
+	}
+	return lines
+}
+
+func formatExplainedValue(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/skatteetaten/ao/pkg/deploymentspec"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_formatProvenances(t *testing.T) {
+	provenances := []deploymentspec.Provenance{
+		{
+			Path:   "/route/foo/host",
+			Value:  "foo.test",
+			Source: "test/app.json",
+			Sources: []deploymentspec.Source{
+				{Name: "default", Value: "@name@"},
+				{Name: "app.json", Value: "foo"},
+				{Name: "test/app.json", Value: "foo.test"},
+			},
+		},
+		{
+			Path:    "/replicas",
+			Value:   2,
+			Source:  "about.json",
+			Sources: []deploymentspec.Source{{Name: "about.json", Value: 2}},
+		},
+	}
+
+	expected := []string{
+		"/route/foo/host = \"foo.test\"\ttest/app.json",
+		"  overrides \"foo\"\tapp.json",
+		"  overrides \"@name@\"\tdefault",
+		"/replicas = 2\tabout.json",
+	}
+	assert.Equal(t, expected, formatProvenances(provenances))
+}
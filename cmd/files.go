@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+	"github.com/skatteetaten/ao/pkg/prompt"
+	"github.com/skatteetaten/ao/pkg/service"
+	"github.com/spf13/cobra"
+)
+
+const rmLong = `Remove a file from the current AuroraConfig.
+Removing a file referred to with baseFile or envFile from other files, or used by default by other files
+without such a key, fails unless --force is given.
+If the file is an application deployment, you are offered to delete its running deployments once the file is removed.`
+
+const mvLong = `Move or rename a file in the current AuroraConfig.
+If the extension changes, the file is converted between JSON and YAML. Files referring to the
+moved file with baseFile or envFile must be updated as well, use --update-references to do so.
+Files using the moved file by default, such as utv/foo.json using foo.json as its base file,
+get the baseFile, envFile or globalFile key added with --update-references.`
+
+const cpLong = `Copy a file in the current AuroraConfig.
+If the extension of the copy differs from the original, the copy is converted between JSON and YAML.`
+
+const filesExample = `  # Remove utv/foo.json, asking whether to delete the running deployments of utv/foo
+  ao rm utv/foo
+
+  # Rename foo.json to bar.json and update baseFile in files referring to it
+  ao mv foo.json bar.json --update-references
+
+  # Copy test/foo.json to prod/foo.yaml
+  ao cp test/foo prod/foo.yaml`
+
+var (
+	flagRmForce            bool
+	flagRmYes              bool
+	flagRmKeepDeployments  bool
+	flagMvUpdateReferences bool
+)
+
+var (
+	rmCmd = &cobra.Command{
+		Use:         "rm <file>",
+		Short:       "Remove a file from the current AuroraConfig",
+		Long:        rmLong,
+		Annotations: map[string]string{"type": "remote"},
+		Example:     filesExample,
+		RunE:        RemoveFile,
+	}
+
+	mvCmd = &cobra.Command{
+		Use:         "mv <source> <destination>",
+		Short:       "Move or rename a file in the current AuroraConfig",
+		Long:        mvLong,
+		Annotations: map[string]string{"type": "remote"},
+		Example:     filesExample,
+		RunE:        MoveFile,
+	}
+
+	cpCmd = &cobra.Command{
+		Use:         "cp <source> <destination>",
+		Short:       "Copy a file in the current AuroraConfig",
+		Long:        cpLong,
+		Annotations: map[string]string{"type": "remote"},
+		Example:     filesExample,
+		RunE:        CopyFile,
+	}
+)
+
+func init() {
+	RootCmd.AddCommand(rmCmd)
+	RootCmd.AddCommand(mvCmd)
+	RootCmd.AddCommand(cpCmd)
+
+	rmCmd.Flags().BoolVar(&flagRmForce, "force", false, "remove the file even if other files refer to it")
+	rmCmd.Flags().BoolVarP(&flagRmYes, "yes", "y", false, "Suppress prompts and accept removal, including deletion of running deployments")
+	rmCmd.Flags().BoolVar(&flagRmKeepDeployments, "keep-deployments", false, "do not offer to delete running deployments")
+	mvCmd.Flags().BoolVar(&flagMvUpdateReferences, "update-references", false, "Update baseFile and envFile in files referring to the moved file")
+}
+
+// RemoveFile is the entry point of the `rm` cli command
+func RemoveFile(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return cmd.Usage()
+	}
+
+	ac, err := DefaultAPIClient.GetAuroraConfig()
+	if err != nil {
+		return err
+	}
+	fileName, err := findAuroraConfigFile(args[0], ac.FileNames())
+	if err != nil {
+		return err
+	}
+
+	impact, err := ac.FileImpact(fileName)
+	if err != nil {
+		return err
+	}
+	if references := impact.BreakingReferences(); len(references) > 0 && !flagRmForce {
+		return errors.Errorf("%s is referenced from %s, use --force to remove it anyway", fileName, formatReferrers(references))
+	}
+	printFileImpact(cmd, fileName, impact, "would disappear")
+
+	if !flagRmYes && !prompt.Confirm(fmt.Sprintf("Do you want to remove %s?", fileName), false) {
+		return errors.New("Did not remove any files")
+	}
+
+	// The running deployments are found before the file is removed, since their deploy specs can not be read afterwards
+	var deployInfos []DeploymentInfo
+	if len(impact.ApplicationDeploymentRefs) > 0 && !flagRmKeepDeployments {
+		if deployInfos, err = getRunningDeployments(impact.ApplicationDeploymentRefs); err != nil {
+			return err
+		}
+	}
+
+	loaded := ac.Copy()
+	if err := ac.RemoveFile(fileName); err != nil {
+		return err
+	}
+	if err := DefaultAPIClient.SaveAuroraConfig(loaded, ac); err != nil {
+		return err
+	}
+	cmd.Printf("%s has been removed\n", fileName)
+
+	return offerToDeleteDeployments(cmd, deployInfos)
+}
+
+// MoveFile is the entry point of the `mv` cli command
+func MoveFile(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return cmd.Usage()
+	}
+
+	ac, err := DefaultAPIClient.GetAuroraConfig()
+	if err != nil {
+		return err
+	}
+	source, err := findAuroraConfigFile(args[0], ac.FileNames())
+	if err != nil {
+		return err
+	}
+
+	impact, err := ac.FileImpact(source)
+	if err != nil {
+		return err
+	}
+
+	loaded := ac.Copy()
+	moved, references, err := ac.MoveFile(source, args[1], flagMvUpdateReferences)
+	if err != nil {
+		return err
+	}
+	printFileImpact(cmd, source, &auroraconfig.FileImpact{ApplicationDeploymentRefs: impact.ApplicationDeploymentRefs, UsedBy: impact.UsedBy}, "will disappear, running deployments are not deleted")
+
+	if err := DefaultAPIClient.SaveAuroraConfig(loaded, ac); err != nil {
+		return err
+	}
+
+	cmd.Printf("%s has been moved to %s\n", source, moved.Name)
+	for _, reference := range references {
+		cmd.Printf("%s in %s has been updated\n", reference.Key, reference.File)
+	}
+	return nil
+}
+
+// CopyFile is the entry point of the `cp` cli command
+func CopyFile(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return cmd.Usage()
+	}
+
+	ac, err := DefaultAPIClient.GetAuroraConfig()
+	if err != nil {
+		return err
+	}
+	source, err := findAuroraConfigFile(args[0], ac.FileNames())
+	if err != nil {
+		return err
+	}
+
+	copied, err := ac.CopyFile(source, args[1])
+	if err != nil {
+		return err
+	}
+	if err := DefaultAPIClient.CreateAuroraConfigFile(copied); err != nil {
+		return err
+	}
+
+	cmd.Printf("%s has been copied to %s\n", source, copied.Name)
+	return nil
+}
+
+// findAuroraConfigFile finds the single file matching search
+func findAuroraConfigFile(search string, fileNames auroraconfig.FileNames) (string, error) {
+	matches := auroraconfig.FindMatches(search, fileNames, true)
+	if len(matches) == 0 {
+		return "", errors.Errorf("No matches for %s", search)
+	} else if len(matches) > 1 {
+		return "", errors.Errorf("Search matched more than one file. Search must be more specific.\n%v", matches)
+	}
+	return matches[0], nil
+}
+
+func formatReferrers(references []auroraconfig.Reference) string {
+	var referrers []string
+	for _, reference := range references {
+		referrers = append(referrers, reference.File+" ("+reference.Key+")")
+	}
+	return strings.Join(referrers, ", ")
+}
+
+func printFileImpact(cmd *cobra.Command, fileName string, impact *auroraconfig.FileImpact, disappear string) {
+	if len(impact.References) > 0 {
+		cmd.Printf("Files referring to %s: %s\n", fileName, formatReferrers(impact.References))
+	}
+	if len(impact.ImplicitReferences) > 0 {
+		cmd.Printf("Files using %s by default: %s\n", fileName, formatReferrers(impact.ImplicitReferences))
+	}
+	if len(impact.ApplicationDeploymentRefs) > 0 {
+		cmd.Printf("ApplicationDeploymentRefs that %s: %s\n", disappear, strings.Join(impact.ApplicationDeploymentRefs, ", "))
+	}
+	if len(impact.UsedBy) > 0 {
+		cmd.Printf("ApplicationDeploymentRefs using %s: %s\n", fileName, strings.Join(impact.UsedBy, ", "))
+	}
+}
+
+// getRunningDeployments returns the running deployments of the given ApplicationDeploymentRefs
+func getRunningDeployments(applications []string) ([]DeploymentInfo, error) {
+	specs, err := service.GetFilteredDeploymentSpecs(DefaultAPIClient, applications, "")
+	if err != nil {
+		return nil, err
+	}
+	return getDeployedApplications(getApplicationDeploymentClient, specs, AOSession.AuroraConfig, pFlagToken)
+}
+
+// offerToDeleteDeployments deletes the given running deployments after confirmation
+func offerToDeleteDeployments(cmd *cobra.Command, deployInfos []DeploymentInfo) error {
+	if len(deployInfos) == 0 {
+		return nil
+	}
+
+	if !getDeleteConfirmation(flagRmYes, deployInfos, cmd.OutOrStdout()) {
+		cmd.Println("Keeping running deployments")
+		return nil
+	}
+	return deleteDeployments(deployInfos, AOSession.AuroraConfig, cmd.OutOrStdout())
+}
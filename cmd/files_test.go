@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_findAuroraConfigFile(t *testing.T) {
+	fileNames := auroraconfig.FileNames{"about.json", "foo.json", "utv/about.json", "utv/foo.yaml", "utv/foobar.json"}
+
+	fileName, err := findAuroraConfigFile("utv/foo", fileNames)
+	assert.NoError(t, err)
+	assert.Equal(t, "utv/foo.yaml", fileName)
+
+	_, err = findAuroraConfigFile("test/foo", fileNames)
+	assert.EqualError(t, err, "No matches for test/foo")
+}
+
+func Test_formatReferrers(t *testing.T) {
+	references := []auroraconfig.Reference{
+		{File: "utv/foo.json", Key: "baseFile", Value: "bar.json"},
+		{File: "test/foo.json", Key: "baseFile", Value: "bar.json"},
+	}
+	assert.Equal(t, "utv/foo.json (baseFile), test/foo.json (baseFile)", formatReferrers(references))
+}
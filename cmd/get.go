@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/skatteetaten/ao/pkg/auroraconfig"
 	"github.com/skatteetaten/ao/pkg/deploymentspec"
+	"github.com/skatteetaten/ao/pkg/service"
 
 	"encoding/json"
 	"sort"
@@ -18,6 +19,7 @@ var (
 	flagAsList       bool
 	flagNoDefaults   bool
 	flagIgnoreErrors bool
+	flagLocalSpec    bool
 )
 
 var (
@@ -72,6 +74,8 @@ func init() {
 	getSpecCmd.Flags().BoolVar(&flagNoDefaults, "no-defaults", false, "exclude default values from output")
 	getSpecCmd.Flags().BoolVar(&flagJSON, "json", false, "print deploy spec as json")
 	getSpecCmd.Flags().BoolVar(&flagIgnoreErrors, "ignore-errors", false, "suppresses errors from spec assembly. NB: may return incomplete deploy spec, use with care")
+	getSpecCmd.Flags().BoolVar(&flagLocalSpec, "local", false, "render the effective configuration from the files in the local checkout, without defaults")
+	addSelectorFlag(getSpecCmd)
 	getDeploymentsCmd.Flags().BoolVar(&flagAsList, "list", false, "print ApplicationDeploymentRefs as a list")
 }
 
@@ -212,25 +216,44 @@ func makeColumnPattern(columnCount int) string {
 
 // PrintDeploySpec is the main method for the `get spec` cli command
 func PrintDeploySpec(cmd *cobra.Command, args []string) error {
-	if len(args) > 2 || len(args) < 1 {
+	search, ok := getApplicationSearch(args)
+	if !ok {
 		return cmd.Usage()
 	}
 
+	if flagLocalSpec {
+		if flagSelector != "" || auroraconfig.IsGroup(search) {
+			return errors.New("--selector and groups can not be combined with --local")
+		}
+		return printLocalDeploySpec(cmd, search)
+	}
+
 	fileNames, err := DefaultAPIClient.GetFileNames()
 	if err != nil {
 		return err
 	}
 
-	search := args[0]
-	if len(args) == 2 {
-		search = fmt.Sprintf("%s/%s", args[0], args[1])
-	}
-
-	matches := auroraconfig.FindMatches(search, fileNames.GetApplicationDeploymentRefs(), false)
-	if len(matches) == 0 {
-		return errors.Errorf("No matches for %s", search)
-	} else if len(matches) > 1 {
-		return errors.Errorf("Search matched more than one file. Search must be more specific.\n%v", matches)
+	var matches []string
+	if flagSelector != "" || auroraconfig.IsGroup(search) {
+		specs, err := getSelectedDeploySpecs(search, fileNames)
+		if err != nil {
+			return err
+		}
+		if len(specs) > 1 && !flagJSON {
+			header, rows := GetDeploySpecTable(specs, "")
+			DefaultTablePrinter(header, rows, cmd.OutOrStdout())
+			return nil
+		}
+		for _, spec := range specs {
+			matches = append(matches, spec.GetString("applicationDeploymentRef"))
+		}
+	} else {
+		matches = auroraconfig.FindMatches(search, fileNames.GetApplicationDeploymentRefs(), false)
+		if len(matches) == 0 {
+			return errors.Errorf("No matches for %s", search)
+		} else if len(matches) > 1 {
+			return errors.Errorf("Search matched more than one file. Search must be more specific.\n%v", matches)
+		}
 	}
 
 	if !flagJSON {
@@ -263,6 +286,63 @@ func PrintDeploySpec(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// getSelectedDeploySpecs gets the deploy specs of the applications matching search, which may be a group, and flagSelector
+func getSelectedDeploySpecs(search string, fileNames auroraconfig.FileNames) ([]deploymentspec.DeploymentSpec, error) {
+	var applications []string
+	var err error
+	if auroraconfig.IsGroup(search) {
+		applications, err = service.GetGroupApplications(DefaultAPIClient, search, getConfigGroups(), aoConfigSource, nil)
+	} else {
+		applications, err = auroraconfig.GetApplicationRefs(fileNames, search, nil)
+	}
+	if err != nil {
+		return nil, err
+	} else if len(applications) == 0 {
+		return nil, errors.Errorf("No matches for %s", search)
+	}
+
+	specs, err := DefaultAPIClient.GetAuroraDeploySpec(applications, true, flagIgnoreErrors)
+	if err != nil {
+		return nil, err
+	}
+	specs, err = selectDeploymentSpecs(specs)
+	if err != nil {
+		return nil, err
+	} else if len(specs) == 0 {
+		return nil, errors.Errorf("No applications match %s", flagSelector)
+	}
+	return specs, nil
+}
+
+func printLocalDeploySpec(cmd *cobra.Command, search string) error {
+	_, ac, err := loadLocalAuroraConfig()
+	if err != nil {
+		return err
+	}
+
+	applicationDeploymentRef, err := findApplicationDeploymentRef(search, ac.FileNames())
+	if err != nil {
+		return err
+	}
+
+	spec, err := ac.RenderSpec(applicationDeploymentRef)
+	if err != nil {
+		return err
+	}
+
+	if !flagJSON {
+		cmd.Println(spec.Format())
+		return nil
+	}
+
+	data, err := json.MarshalIndent([]deploymentspec.DeploymentSpec{spec.DeploymentSpec()}, "", "  ")
+	if err != nil {
+		return err
+	}
+	cmd.Println(string(data))
+	return nil
+}
+
 // PrintFile is the main method for the `get file` cli command
 func PrintFile(cmd *cobra.Command, args []string) error {
 	fileNames, err := DefaultAPIClient.GetFileNames()
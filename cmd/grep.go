@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+	"github.com/spf13/cobra"
+)
+
+const grepLong = `Search for values in all files of the AuroraConfig.
+If the argument starts with / it is a JSON pointer, and every value at or below that key is listed.
+Pointer segments may contain wildcards such as *. Otherwise the argument is a regular expression
+matched against the values. Use --value to match values below a JSON pointer.`
+
+const grepExample = `  # Find every database setting
+  ao grep /database
+
+  # Find every value mentioning an oracle host, ignoring case
+  ao grep 'oracle-db[0-9]+' --ignore-case
+
+  # Find config variables ending with _URL in the utv environment
+  ao grep '/config/*_URL' --files utv
+
+  # Find applications with sts enabled
+  ao grep /sts --value true --json`
+
+var (
+	flagGrepValue      string
+	flagGrepFiles      string
+	flagGrepIgnoreCase bool
+	flagGrepJSON       bool
+)
+
+var grepCmd = &cobra.Command{
+	Use:         "grep <pattern|json-pointer>",
+	Short:       "Search for keys and values in the AuroraConfig",
+	Long:        grepLong,
+	Annotations: map[string]string{"type": "remote"},
+	Example:     grepExample,
+	RunE:        Grep,
+}
+
+func init() {
+	RootCmd.AddCommand(grepCmd)
+	grepCmd.Flags().StringVar(&flagGrepValue, "value", "", "regular expression the values must match")
+	grepCmd.Flags().StringVar(&flagGrepFiles, "files", "", "only search files matching this selector, e.g. an environment or a glob")
+	grepCmd.Flags().BoolVar(&flagGrepIgnoreCase, "ignore-case", false, "ignore case when matching values")
+	grepCmd.Flags().BoolVar(&flagGrepJSON, "json", false, "print the matches as json")
+}
+
+// Grep is the entry point of the `grep` cli command
+func Grep(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return cmd.Usage()
+	}
+
+	pointer, pattern := "", args[0]
+	if strings.HasPrefix(args[0], "/") {
+		pointer, pattern = args[0], flagGrepValue
+	} else if flagGrepValue != "" {
+		return errors.New("--value can only be used with a JSON pointer")
+	}
+
+	query, err := auroraconfig.NewSearchQuery(pointer, pattern, flagGrepIgnoreCase)
+	if err != nil {
+		return err
+	}
+
+	ac, err := DefaultAPIClient.GetAuroraConfig()
+	if err != nil {
+		return err
+	}
+
+	fileNames := ac.FileNames()
+	if flagGrepFiles != "" {
+		fileNames = fileNames.Select(flagGrepFiles)
+		if len(fileNames) == 0 {
+			return errors.Errorf("No files matching %s", flagGrepFiles)
+		}
+	}
+
+	matches, err := ac.Search(query, fileNames)
+	if err != nil {
+		return err
+	}
+
+	if flagGrepJSON {
+		if matches == nil {
+			matches = []auroraconfig.SearchMatch{}
+		}
+		data, err := json.MarshalIndent(matches, "", "  ")
+		if err != nil {
+			return err
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+
+	if len(matches) == 0 {
+		return errors.Errorf("No matches for %s", args[0])
+	}
+	header, rows := getSearchMatchTable(matches)
+	DefaultTablePrinter(header, rows, cmd.OutOrStdout())
+	return nil
+}
+
+func getSearchMatchTable(matches []auroraconfig.SearchMatch) (string, []string) {
+	var rows []string
+	for _, match := range matches {
+		rows = append(rows, fmt.Sprintf("%s\t%s\t%s", match.File, match.Path, formatExplainedValue(match.Value)))
+	}
+	return "FILE\tPATH\tVALUE", rows
+}
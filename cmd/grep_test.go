@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_getSearchMatchTable(t *testing.T) {
+	matches := []auroraconfig.SearchMatch{
+		{File: "foo.json", Path: "/database/foo", Value: "auto"},
+		{File: "utv/foo.yaml", Path: "/sts", Value: true},
+	}
+
+	header, rows := getSearchMatchTable(matches)
+
+	assert.Equal(t, "FILE\tPATH\tVALUE", header)
+	assert.Equal(t, []string{"foo.json\t/database/foo\t\"auto\"", "utv/foo.yaml\t/sts\ttrue"}, rows)
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+	"github.com/skatteetaten/ao/pkg/client"
+	"github.com/skatteetaten/ao/pkg/service"
+	"github.com/spf13/cobra"
+)
+
+const groupLong = `List and expand named groups of applications.
+A group is used like an application search, as in 'ao deploy @payments'.
+Groups are defined in about-groups.json or about-groups.yaml in the root folder of the AuroraConfig,
+or in "groups" in ` + aoConfigSource + `, which replaces groups with the same name in the AuroraConfig.
+Each group has a list of ApplicationDeploymentRefs and other groups, for example:
+
+  {
+    "payments": ["prod/pay-api", "prod/pay-worker"],
+    "all": ["@payments", "prod/crm"]
+  }`
+
+const groupExample = `  # List all groups
+  ao group list
+
+  # Show the applications in payments
+  ao group show @payments
+
+  # Deploy all applications in payments, except prod/pay-worker
+  ao deploy @payments -e prod/pay-worker`
+
+const aoConfigSource = ".ao-config.json"
+
+var (
+	groupCmd = &cobra.Command{
+		Use:         "group",
+		Short:       "List and expand named groups of applications",
+		Long:        groupLong,
+		Annotations: map[string]string{"type": "remote"},
+		Example:     groupExample,
+	}
+
+	groupListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List the groups of the AuroraConfig",
+		RunE:  ListGroups,
+	}
+
+	groupShowCmd = &cobra.Command{
+		Use:   "show <@group>",
+		Short: "Show the applications in a group",
+		RunE:  ShowGroup,
+	}
+)
+
+func init() {
+	RootCmd.AddCommand(groupCmd)
+	groupCmd.AddCommand(groupListCmd)
+	groupCmd.AddCommand(groupShowCmd)
+}
+
+// ListGroups is the entry point of the `group list` cli command
+func ListGroups(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return cmd.Usage()
+	}
+
+	fileNames, err := DefaultAPIClient.GetFileNames()
+	if err != nil {
+		return err
+	}
+	groups, err := service.LoadGroups(DefaultAPIClient, fileNames, getConfigGroups(), aoConfigSource)
+	if err != nil {
+		return err
+	}
+	if len(groups) == 0 {
+		return errors.New("No groups are defined")
+	}
+
+	DefaultTablePrinter("GROUP\tSOURCE\tMEMBERS", getGroupRows(groups), cmd.OutOrStdout())
+	return nil
+}
+
+// ShowGroup is the entry point of the `group show` cli command
+func ShowGroup(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return cmd.Usage()
+	}
+
+	applications, err := service.GetGroupApplications(DefaultAPIClient, args[0], getConfigGroups(), aoConfigSource, nil)
+	if err != nil {
+		return err
+	}
+
+	DefaultTablePrinter("APPLICATIONDEPLOYMENTREF", applications, cmd.OutOrStdout())
+	return nil
+}
+
+func getGroupRows(groups auroraconfig.Groups) []string {
+	var names []string
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var rows []string
+	for _, name := range names {
+		group := groups[name]
+		rows = append(rows, auroraconfig.GroupPrefix+name+"\t"+group.Source+"\t"+strings.Join(group.Members, " "))
+	}
+	return rows
+}
+
+// getApplications returns the applications matching search, which may be a group, except those matching excludes
+func getApplications(apiClient client.AuroraConfigClient, search string, excludes []string) ([]string, error) {
+	if auroraconfig.IsGroup(search) {
+		return service.GetGroupApplications(apiClient, search, getConfigGroups(), aoConfigSource, excludes)
+	}
+	return service.GetApplications(apiClient, search, excludes)
+}
+
+func getConfigGroups() map[string][]string {
+	if AOConfig == nil {
+		return nil
+	}
+	return AOConfig.Groups
+}
@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_getGroupRows(t *testing.T) {
+	groups := auroraconfig.Groups{}
+	groups.Add("about-groups.json", map[string][]string{
+		"payments": {"prod/pay-api", "prod/pay-worker"},
+		"all":      {"@payments", "prod/crm"},
+	})
+	groups.Add(aoConfigSource, map[string][]string{"payments": {"prod/pay-api"}})
+
+	assert.Equal(t, []string{
+		"@all\tabout-groups.json\t@payments prod/crm",
+		"@payments\t.ao-config.json\tprod/pay-api",
+	}, getGroupRows(groups))
+}
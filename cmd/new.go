@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+	"github.com/skatteetaten/ao/pkg/prompt"
+	"github.com/spf13/cobra"
+)
+
+const newLong = `Create a new application or environment from a template.
+Templates are read from --template-dir, from the templates folder of the AuroraConfig, or the
+templates shipped with ao are used, in that order. A template consists of the files
+about-app-base and about-app for applications, and about-env for environments, in json or yaml.
+They are rendered to <name>, <env>/<name> and <env>/about. Values such as {{.cluster}} are asked for,
+unless given with --set. {{.name}} and {{.env}} are given by the arguments.
+An existing base file is kept as it is. The result is validated before any file is created.`
+
+const newExample = `  # Create test/foo, asking for the values in the template
+  ao new app test/foo
+
+  # Create the environment utv05 without prompts
+  ao new env utv05 --set cluster=utv --set admin=APP_PaaS_drift
+
+  # Create an application from templates in a local directory
+  ao new app test/bar --template-dir ~/templates`
+
+var (
+	flagNewTemplateDir string
+	flagNewValues      []string
+)
+
+var (
+	newCmd = &cobra.Command{
+		Use:         "new",
+		Short:       "Create new applications or environments from templates",
+		Long:        newLong,
+		Annotations: map[string]string{"type": "remote"},
+		Example:     newExample,
+	}
+
+	newAppCmd = &cobra.Command{
+		Use:   "app <environment/application>",
+		Short: "Create a new application from a template",
+		RunE:  NewApplication,
+	}
+
+	newEnvCmd = &cobra.Command{
+		Use:   "env <environment>",
+		Short: "Create a new environment from a template",
+		RunE:  NewEnvironment,
+	}
+)
+
+func init() {
+	RootCmd.AddCommand(newCmd)
+	newCmd.AddCommand(newAppCmd)
+	newCmd.AddCommand(newEnvCmd)
+
+	for _, command := range []*cobra.Command{newAppCmd, newEnvCmd} {
+		command.Flags().StringVar(&flagNewTemplateDir, "template-dir", "", "directory with templates")
+		command.Flags().StringArrayVar(&flagNewValues, "set", []string{}, "template value in the form key=value")
+	}
+}
+
+// NewApplication is the main method for the `new app` cli command
+func NewApplication(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return cmd.Usage()
+	}
+	parts := strings.Split(args[0], auroraconfig.Separator)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return errors.Errorf("%s is not a valid ApplicationDeploymentRef (environment/application)", args[0])
+	}
+
+	return newFromTemplate(cmd, auroraconfig.TemplateApp, map[string]string{
+		auroraconfig.TemplateFieldEnv:  parts[0],
+		auroraconfig.TemplateFieldName: parts[1],
+	})
+}
+
+// NewEnvironment is the main method for the `new env` cli command
+func NewEnvironment(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return cmd.Usage()
+	}
+	if strings.Contains(args[0], auroraconfig.Separator) {
+		return errors.Errorf("%s is not a valid environment name", args[0])
+	}
+
+	return newFromTemplate(cmd, auroraconfig.TemplateEnv, map[string]string{
+		auroraconfig.TemplateFieldEnv: args[0],
+	})
+}
+
+func newFromTemplate(cmd *cobra.Command, kind string, values map[string]string) error {
+	for _, value := range flagNewValues {
+		keyValue := strings.SplitN(value, "=", 2)
+		if len(keyValue) != 2 {
+			return errors.Errorf("%s is not in the form key=value", value)
+		}
+		if _, given := values[keyValue[0]]; !given {
+			values[keyValue[0]] = keyValue[1]
+		}
+	}
+
+	ac, err := DefaultAPIClient.GetAuroraConfig()
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := loadNewTemplate(kind, ac)
+	if err != nil {
+		return err
+	}
+
+	for _, field := range tmpl.Fields() {
+		if _, given := values[field]; given {
+			continue
+		}
+		values[field] = prompt.Input(field + ":")
+		if values[field] == "" {
+			return errors.Errorf("No value given for %s", field)
+		}
+	}
+
+	files, err := tmpl.Render(values)
+	if err != nil {
+		return err
+	}
+
+	fileNames := ac.FileNames()
+	var created []auroraconfig.File
+	for _, file := range files {
+		existing, err := fileNames.Find(strings.TrimSuffix(file.Name, filepath.Ext(file.Name)))
+		if err != nil {
+			created = append(created, file)
+			continue
+		}
+		if strings.Contains(file.Name, auroraconfig.Separator) {
+			return errors.Errorf("%s already exists", existing)
+		}
+		cmd.Printf("Using existing %s\n", existing)
+	}
+
+	ac.Files = append(ac.Files, created...)
+	warnings, err := DefaultAPIClient.ValidateAuroraConfig(ac, false)
+	if err != nil {
+		return err
+	}
+	if warnings != "" {
+		cmd.Println(warnings)
+	}
+
+	for i := range created {
+		if err := DefaultAPIClient.CreateAuroraConfigFile(&created[i]); err != nil {
+			return err
+		}
+		cmd.Printf("%s has been created\n", created[i].Name)
+	}
+	return nil
+}
+
+// loadNewTemplate loads the template from --template-dir, from the AuroraConfig, or the default template
+func loadNewTemplate(kind string, ac *auroraconfig.AuroraConfig) (*auroraconfig.Template, error) {
+	if flagNewTemplateDir != "" {
+		files, err := readTemplateDir(flagNewTemplateDir)
+		if err != nil {
+			return nil, err
+		}
+		tmpl, err := auroraconfig.LoadTemplate(kind, files)
+		if err != nil {
+			return nil, err
+		}
+		if tmpl == nil {
+			return nil, errors.Errorf("No %s template in %s", kind, flagNewTemplateDir)
+		}
+		return tmpl, nil
+	}
+
+	tmpl, err := auroraconfig.LoadTemplate(kind, ac.TemplateFiles())
+	if err != nil || tmpl != nil {
+		return tmpl, err
+	}
+	return auroraconfig.DefaultTemplate(kind)
+}
+
+func readTemplateDir(dir string) ([]auroraconfig.File, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []auroraconfig.File
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, auroraconfig.File{Name: entry.Name(), Contents: string(data)})
+	}
+	return files, nil
+}
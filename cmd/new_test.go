@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_loadNewTemplate(t *testing.T) {
+	ac := &auroraconfig.AuroraConfig{Files: []auroraconfig.File{
+		{Name: "templates/about-env.json", Contents: `{"cluster": "{{.cluster}}", "segment": "{{.segment}}"}`},
+	}}
+
+	tmpl, err := loadNewTemplate(auroraconfig.TemplateEnv, ac)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"cluster", "segment"}, tmpl.Fields())
+
+	tmpl, err = loadNewTemplate(auroraconfig.TemplateApp, ac)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"groupId", "version"}, tmpl.Fields())
+
+	dir := t.TempDir()
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "about-app.yaml"), []byte("---\nversion: \"{{.version}}\"\n"), 0644))
+	flagNewTemplateDir = dir
+	defer func() { flagNewTemplateDir = "" }()
+
+	tmpl, err = loadNewTemplate(auroraconfig.TemplateApp, ac)
+	assert.NoError(t, err)
+	assert.Len(t, tmpl.Files, 1)
+	assert.Equal(t, auroraconfig.FormatYaml, tmpl.Files[0].Format)
+
+	_, err = loadNewTemplate(auroraconfig.TemplateEnv, ac)
+	assert.EqualError(t, err, "No env template in "+dir)
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+	"github.com/skatteetaten/ao/pkg/deploymentspec"
+	"github.com/skatteetaten/ao/pkg/prompt"
+	"github.com/spf13/cobra"
+)
+
+const promoteLong = `Promote configuration from one application to another, typically from test to prod.
+The effective values of the given keys in the deploy spec of the source application are written
+to the application file of the target, e.g. prod/app.json. Only values set in AuroraConfig files are
+promoted, defaults and values set by Aurora are left for the target to get by itself. The change is shown as a diff and must be
+confirmed before it is saved. Use --deploy to deploy the target application afterwards.`
+
+const promoteExample = `  # Promote the version of app from test to prod
+  ao promote test/app prod/app
+
+  # Promote the version and a config variable, and deploy prod/app
+  ao promote test/app prod/app --keys /version,/config/FEATURE_X --deploy`
+
+var (
+	flagPromoteKeys   []string
+	flagPromoteDeploy bool
+	flagPromoteYes    bool
+)
+
+var promoteCmd = &cobra.Command{
+	Use:         "promote <source applicationDeploymentRef> <target applicationDeploymentRef>",
+	Short:       "Promote version or configuration from one application to another",
+	Long:        promoteLong,
+	Annotations: map[string]string{"type": "remote"},
+	Example:     promoteExample,
+	RunE:        Promote,
+}
+
+func init() {
+	RootCmd.AddCommand(promoteCmd)
+	promoteCmd.Flags().StringSliceVar(&flagPromoteKeys, "keys", []string{"/version"}, "keys to promote")
+	promoteCmd.Flags().BoolVar(&flagPromoteDeploy, "deploy", false, "deploy the target application after saving")
+	promoteCmd.Flags().BoolVarP(&flagPromoteYes, "yes", "y", false, "Suppress prompts and accept the changes")
+}
+
+// Promote is the entry point of the `promote` cli command
+func Promote(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return cmd.Usage()
+	}
+
+	fileNames, err := DefaultAPIClient.GetFileNames()
+	if err != nil {
+		return err
+	}
+	source, err := findApplicationDeploymentRef(args[0], fileNames)
+	if err != nil {
+		return err
+	}
+	target, err := findApplicationDeploymentRef(args[1], fileNames)
+	if err != nil {
+		return err
+	}
+	if source == target {
+		return errors.New("Source and target must be different applications")
+	}
+
+	specs, err := DefaultAPIClient.GetAuroraDeploySpec([]string{source}, true, false)
+	if err != nil {
+		return err
+	}
+	if len(specs) != 1 {
+		return errors.Errorf("Expected one deploy spec for %s, got %d", source, len(specs))
+	}
+	values, err := getPromotedValues(specs[0], flagPromoteKeys, fileNames)
+	if err != nil {
+		return err
+	}
+
+	fileName, err := fileNames.Find(target)
+	if err != nil {
+		return err
+	}
+	file, eTag, err := DefaultAPIClient.GetAuroraConfigFile(fileName)
+	if err != nil {
+		return err
+	}
+	original := *file
+	for _, value := range values {
+		if err := auroraconfig.SetValue(file, value.Path, value.Value); err != nil {
+			return err
+		}
+	}
+
+	diff, err := auroraconfig.Diff(&original, file)
+	if err != nil {
+		return err
+	}
+	if diff == "" {
+		cmd.Printf("%s already has the values of %s\n", target, source)
+	} else {
+		cmd.Print(diff)
+		message := fmt.Sprintf("Do you want to save the changes to %s?", fileName)
+		if !flagPromoteYes && !prompt.Confirm(message, true) {
+			return errors.New("Did not promote any values")
+		}
+		if err := DefaultAPIClient.UpdateAuroraConfigFile(file, eTag); err != nil {
+			return err
+		}
+		cmd.Printf("%s has been updated with %s from %s\n", fileName, strings.Join(flagPromoteKeys, ", "), source)
+	}
+
+	if flagPromoteDeploy {
+		return deployApplications(cmd, []string{target}, flagPromoteYes)
+	}
+	return nil
+}
+
+// getPromotedValues returns the values at or below the keys in the source spec that are set in one of the files in
+// fileNames. An object that has both a value and nested fields, such as route, is promoted through its nested fields only.
+func getPromotedValues(spec deploymentspec.DeploymentSpec, keys []string, fileNames auroraconfig.FileNames) ([]deploymentspec.Provenance, error) {
+	isFile := make(map[string]bool)
+	for _, fileName := range fileNames {
+		isFile[fileName] = true
+	}
+
+	var values []deploymentspec.Provenance
+	for _, key := range keys {
+		if !strings.HasPrefix(key, "/") {
+			return nil, errors.Errorf("%s is not a JSON pointer, it must start with /", key)
+		}
+		provenances, err := spec.Explain(key)
+		if err != nil {
+			return nil, err
+		}
+		for i, provenance := range provenances {
+			if i+1 < len(provenances) && strings.HasPrefix(provenances[i+1].Path, provenance.Path+"/") {
+				continue
+			}
+			if isFile[provenance.Source] {
+				values = append(values, provenance)
+			}
+		}
+	}
+	return values, nil
+}
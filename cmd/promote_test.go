@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+	"github.com/skatteetaten/ao/pkg/deploymentspec"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_getPromotedValues(t *testing.T) {
+	spec := deploymentspec.DeploymentSpec{
+		"version": map[string]interface{}{"source": "test/foo.json", "value": "1.2.0"},
+		"route": map[string]interface{}{
+			"source": "foo.json",
+			"value":  true,
+			"foo": map[string]interface{}{
+				"host": map[string]interface{}{"source": "test/foo.json", "value": "foo-test"},
+				"tls":  map[string]interface{}{"source": "default", "value": false},
+			},
+		},
+		"config": map[string]interface{}{
+			"FOO": map[string]interface{}{"source": "foo.json", "value": "bar"},
+			"BAZ": map[string]interface{}{"source": "static", "value": "qux"},
+		},
+	}
+	fileNames := auroraconfig.FileNames{"about.json", "foo.json", "test/about.json", "test/foo.json"}
+
+	values, err := getPromotedValues(spec, []string{"/version", "/route", "/config"}, fileNames)
+	assert.NoError(t, err)
+
+	var paths []string
+	for _, value := range values {
+		paths = append(paths, value.Path)
+	}
+	assert.Equal(t, []string{"/version", "/route/foo/host", "/config/FOO"}, paths)
+
+	_, err = getPromotedValues(spec, []string{"version"}, fileNames)
+	assert.EqualError(t, err, "version is not a JSON pointer, it must start with /")
+
+	_, err = getPromotedValues(spec, []string{"/config/QUX"}, fileNames)
+	assert.EqualError(t, err, "/config/QUX is not set in the deployment spec")
+}
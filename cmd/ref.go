@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/prompt"
+	"github.com/skatteetaten/ao/pkg/session"
+	"github.com/skatteetaten/ao/pkg/versioncontrol"
+	"github.com/spf13/cobra"
+)
+
+const refLong = `Manage refs (git branches) of the AuroraConfig repository through the local checkout.
+A ref can be used for bigger restructurings: create it, select it with --ref or 'ao adm update-ref',
+deploy from it to dev, and merge it back when done.`
+
+const refExample = `  # Create the ref restructure from master and use it for later commands
+  ao ref create restructure --use
+
+  # Show the changes made in restructure since it was created
+  ao ref diff restructure
+
+  # Merge restructure into master and push the result
+  ao ref merge restructure`
+
+var (
+	flagRefFrom     string
+	flagRefInto     string
+	flagRefUse      bool
+	flagRefNameOnly bool
+	flagRefYes      bool
+)
+
+var (
+	refCmd = &cobra.Command{
+		Use:         "ref",
+		Short:       "List, create, compare and merge AuroraConfig refs",
+		Long:        refLong,
+		Annotations: map[string]string{"type": "local"},
+		Example:     refExample,
+	}
+
+	refListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List the refs of the AuroraConfig repository",
+		RunE:  ListRefs,
+	}
+
+	refCreateCmd = &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create a ref from another ref",
+		RunE:  CreateRef,
+	}
+
+	refDiffCmd = &cobra.Command{
+		Use:   "diff <name>",
+		Short: "Show the changes made in a ref since it was created",
+		RunE:  DiffRef,
+	}
+
+	refMergeCmd = &cobra.Command{
+		Use:   "merge <name>",
+		Short: "Merge a ref into another ref and push the result",
+		Long: `Merge a ref into another ref in the local checkout. The merged AuroraConfig is validated
+before it is pushed. If the merge has conflicts it is aborted, and the conflicting files are listed.
+The merge fails if the local branch of the ref to merge into has commits that are not pushed.
+The branch checked out before the merge is checked out again afterwards.`,
+		RunE: MergeRef,
+	}
+)
+
+func init() {
+	RootCmd.AddCommand(refCmd)
+	refCmd.AddCommand(refListCmd)
+	refCmd.AddCommand(refCreateCmd)
+	refCmd.AddCommand(refDiffCmd)
+	refCmd.AddCommand(refMergeCmd)
+
+	refCreateCmd.Flags().StringVar(&flagRefFrom, "from", "master", "ref to create the new ref from")
+	refCreateCmd.Flags().BoolVar(&flagRefUse, "use", false, "use the new ref for later commands, like 'ao adm update-ref'")
+	refDiffCmd.Flags().StringVar(&flagRefFrom, "from", "master", "ref to compare with")
+	refDiffCmd.Flags().BoolVar(&flagRefNameOnly, "name-only", false, "only show the names of changed files")
+	refMergeCmd.Flags().StringVar(&flagRefInto, "into", "master", "ref to merge into")
+	refMergeCmd.Flags().BoolVarP(&flagRefYes, "yes", "y", false, "Suppress prompts and push the merge")
+}
+
+// ListRefs is the entry point of the `ref list` cli command
+func ListRefs(cmd *cobra.Command, args []string) error {
+	gitRoot, err := findGitRoot()
+	if err != nil {
+		return err
+	}
+	refs, err := versioncontrol.ListRefs(gitRoot)
+	if err != nil {
+		return err
+	}
+
+	var rows []string
+	for _, ref := range refs {
+		current := ""
+		if ref == DefaultAPIClient.RefName {
+			current = "Yes"
+		}
+		rows = append(rows, fmt.Sprintf("%s\t%s", ref, current))
+	}
+	DefaultTablePrinter("REF\tCURRENT", rows, cmd.OutOrStdout())
+	return nil
+}
+
+// CreateRef is the entry point of the `ref create` cli command
+func CreateRef(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return cmd.Usage()
+	}
+	gitRoot, err := findGitRoot()
+	if err != nil {
+		return err
+	}
+	if err := versioncontrol.CreateRef(gitRoot, args[0], flagRefFrom); err != nil {
+		return err
+	}
+	cmd.Printf("%s has been created from %s\n", args[0], flagRefFrom)
+
+	if flagRefUse {
+		AOSession.RefName = args[0]
+		if err := session.WriteAOSession(*AOSession, SessionFileLocation); err != nil {
+			return err
+		}
+		cmd.Printf("refName = %s\n", args[0])
+	}
+	return nil
+}
+
+// DiffRef is the entry point of the `ref diff` cli command
+func DiffRef(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return cmd.Usage()
+	}
+	gitRoot, err := findGitRoot()
+	if err != nil {
+		return err
+	}
+	diff, err := versioncontrol.DiffRefs(gitRoot, flagRefFrom, args[0], flagRefNameOnly)
+	if err != nil {
+		return err
+	}
+	if diff == "" {
+		cmd.Printf("%s has no changes since it was created from %s\n", args[0], flagRefFrom)
+		return nil
+	}
+	cmd.Println(diff)
+	return nil
+}
+
+// MergeRef is the entry point of the `ref merge` cli command
+func MergeRef(cmd *cobra.Command, args []string) (err error) {
+	if len(args) != 1 {
+		return cmd.Usage()
+	}
+	name := args[0]
+	if name == flagRefInto {
+		return errors.New("Can not merge a ref into itself")
+	}
+	gitRoot, err := findGitRoot()
+	if err != nil {
+		return err
+	}
+
+	changes, err := versioncontrol.DiffRefs(gitRoot, flagRefInto, name, true)
+	if err != nil {
+		return err
+	}
+	if changes == "" {
+		cmd.Printf("%s has no changes to merge into %s\n", name, flagRefInto)
+		return nil
+	}
+
+	// The merge is done on the branch flagRefInto, the branch checked out before is checked out again afterwards
+	original, err := versioncontrol.CurrentRef(gitRoot)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if checkoutErr := versioncontrol.CheckoutRef(gitRoot, original); checkoutErr != nil && err == nil {
+			err = checkoutErr
+		}
+	}()
+
+	conflicts, err := versioncontrol.MergeRef(gitRoot, name, flagRefInto)
+	if err != nil {
+		return err
+	}
+	if len(conflicts) > 0 {
+		return errors.Errorf("Merging %s into %s has conflicts in %s, the merge has been aborted.\n"+
+			"Merge %s into %s and resolve the conflicts first", name, flagRefInto, strings.Join(conflicts, ", "), flagRefInto, name)
+	}
+
+	if err := validateMerge(cmd, gitRoot); err != nil {
+		if undoErr := versioncontrol.UndoMerge(gitRoot); undoErr != nil {
+			return undoErr
+		}
+		return errors.Wrap(err, "The merge has been undone, it is not valid")
+	}
+
+	cmd.Println(changes)
+	message := fmt.Sprintf("Do you want to push the merge of %s into %s?", name, flagRefInto)
+	if !flagRefYes && !prompt.Confirm(message, false) {
+		if err := versioncontrol.UndoMerge(gitRoot); err != nil {
+			return err
+		}
+		return errors.New("Did not push the merge, it has been undone")
+	}
+
+	if err := versioncontrol.PushRef(gitRoot, flagRefInto); err != nil {
+		return err
+	}
+	cmd.Printf("%s has been merged into %s\n", name, flagRefInto)
+	return nil
+}
+
+func validateMerge(cmd *cobra.Command, gitRoot string) error {
+	ac, err := versioncontrol.CollectAuroraConfigFilesInRepo(DefaultAPIClient.Affiliation, gitRoot)
+	if err != nil {
+		return err
+	}
+	warnings, err := DefaultAPIClient.ValidateAuroraConfig(ac, false)
+	if err != nil {
+		return err
+	}
+	if warnings != "" {
+		cmd.Println(warnings)
+	}
+	return nil
+}
+
+func findGitRoot() (string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	return versioncontrol.FindGitPath(wd)
+}
@@ -42,6 +42,8 @@ var (
 	AOSession *session.AOSession
 	// SessionFileLocation is the location of the file holding session data for the login session
 	SessionFileLocation string
+	// SchemaFileLocation is the location of the AuroraConfig schema cached by `ao validate --update-schema`
+	SchemaFileLocation string
 )
 
 // RootCmd is the root of the entire `ao` cli command structure
@@ -89,6 +91,7 @@ func initialize(cmd *cobra.Command, args []string) error {
 	}
 	CustomConfigLocation = filepath.Join(home, ".ao-config.json")
 	SessionFileLocation = filepath.Join(home, ".ao-session.json")
+	SchemaFileLocation = filepath.Join(home, ".ao-schema.json")
 
 	aoConfig := config.LoadOrCreateAOConfig(CustomConfigLocation)
 
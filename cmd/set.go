@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"strings"
+
 	"github.com/skatteetaten/ao/pkg/auroraconfig"
 	"github.com/spf13/cobra"
 )
@@ -9,7 +11,13 @@ const setExample = `  ao set foo.json /pause true
 
   ao set test/about.json /cluster utv04
 
-  ao set test/foo.yaml /config/IMPORTANT_ENV 'Hello World'`
+  ao set test/foo.yaml /config/IMPORTANT_ENV 'Hello World'
+
+  ao set foo.json /replicas 2 --type int
+
+  ao set test/foo.json /route '{"enabled":true,"path":"/x"}' --type json`
+
+var flagValueType string
 
 var setCmd = &cobra.Command{
 	Use:         "set <file> <path-to-key> <value>",
@@ -21,6 +29,7 @@ var setCmd = &cobra.Command{
 
 func init() {
 	RootCmd.AddCommand(setCmd)
+	setCmd.Flags().StringVar(&flagValueType, "type", auroraconfig.ValueTypeString, "Type of value: "+strings.Join(auroraconfig.ValueTypes, "|"))
 }
 
 // Set is the entry point of the `set` cli command
@@ -30,6 +39,11 @@ func Set(cmd *cobra.Command, args []string) error {
 	}
 	fileName, path, value := args[0], args[1], args[2]
 
+	typedValue, err := auroraconfig.ParseValue(value, flagValueType)
+	if err != nil {
+		return err
+	}
+
 	// Load config file
 	auroraConfigFile, eTag, err := DefaultAPIClient.GetAuroraConfigFile(fileName)
 	if err != nil {
@@ -37,7 +51,7 @@ func Set(cmd *cobra.Command, args []string) error {
 	}
 
 	// Set value
-	if err := auroraconfig.SetValue(auroraConfigFile, path, value); err != nil {
+	if err := auroraconfig.SetValue(auroraConfigFile, path, typedValue); err != nil {
 		return err
 	}
 
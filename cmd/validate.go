@@ -1,18 +1,33 @@
 package cmd
 
 import (
+	"io/ioutil"
+	"net/http"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
 	"github.com/skatteetaten/ao/pkg/versioncontrol"
 	"github.com/spf13/cobra"
 )
 
 var flagFullValidation bool
 var flagRemoteValidation bool
+var flagOfflineValidation bool
+var flagUpdateSchema string
+
+const validateLong = `Validate local modifications in the current AuroraConfig.
+By default the AuroraConfig is validated by the Aurora API. With --offline the local files are
+checked against the schema of known AuroraConfig keys shipped with ao, without calling the API.
+Use --update-schema with the URL or path of a newer schema to refresh it. The schema is checked and
+cached in ~/.ao-schema.json, which is used by --offline instead of the schema shipped with ao.`
 
 var validateCmd = &cobra.Command{
 	Use:         "validate",
 	Short:       "Validate local modifications in the current AuroraConfig",
+	Long:        validateLong,
 	Annotations: map[string]string{"type": "local"},
 	RunE:        Validate,
 }
@@ -22,10 +37,18 @@ func init() {
 	validateCmd.Flags().StringVarP(&flagAuroraConfig, "auroraconfig", "a", "", "AuroraConfig to validate")
 	validateCmd.Flags().BoolVarP(&flagFullValidation, "full", "f", false, "Validate resources")
 	validateCmd.Flags().BoolVarP(&flagRemoteValidation, "remote", "r", false, "Validate remote AuroraConfig instead of local files")
+	validateCmd.Flags().BoolVar(&flagOfflineValidation, "offline", false, "Validate local files against the AuroraConfig schema without calling the API")
+	validateCmd.Flags().StringVar(&flagUpdateSchema, "update-schema", "", "Refresh the AuroraConfig schema used by --offline from a URL or file")
 }
 
 // Validate is the entry point of the `validate` cli command
 func Validate(cmd *cobra.Command, args []string) error {
+	if flagUpdateSchema != "" {
+		return updateSchema(cmd, flagUpdateSchema)
+	}
+	if flagOfflineValidation && flagRemoteValidation {
+		return errors.New("--offline can not be combined with --remote")
+	}
 
 	wd, err := os.Getwd()
 	if err != nil {
@@ -41,6 +64,10 @@ func Validate(cmd *cobra.Command, args []string) error {
 		DefaultAPIClient.Affiliation = flagAuroraConfig
 	}
 
+	if flagOfflineValidation {
+		return validateOffline(cmd, gitRoot)
+	}
+
 	var warnings string
 	if flagRemoteValidation {
 		cmd.Printf("Validating remote AuroraConfig=%s@%s fullValidation=%t\n", DefaultAPIClient.Affiliation, DefaultAPIClient.RefName, flagFullValidation)
@@ -71,3 +98,60 @@ func Validate(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func validateOffline(cmd *cobra.Command, gitRoot string) error {
+	ac, err := versioncontrol.CollectAuroraConfigFilesInRepo(DefaultAPIClient.Affiliation, gitRoot)
+	if err != nil {
+		return err
+	}
+
+	schema := auroraconfig.LoadSchema(SchemaFileLocation)
+	cmd.Printf("Validating AuroraConfig=%s gitRoot=%s offline schemaVersion=%s\n", DefaultAPIClient.Affiliation, gitRoot, schema.Version)
+
+	problems := schema.Validate(ac)
+	if len(problems) == 0 {
+		cmd.Println("OK")
+		return nil
+	}
+
+	cmd.Println("")
+	for _, problem := range problems {
+		cmd.Println(problem)
+	}
+	cmd.Println("")
+
+	return errors.Errorf("AuroraConfig contains %d problems", len(problems))
+}
+
+func updateSchema(cmd *cobra.Command, source string) error {
+	data, err := readSchema(source)
+	if err != nil {
+		return errors.Wrapf(err, "Could not read AuroraConfig schema from %s", source)
+	}
+
+	schema, err := auroraconfig.WriteSchema(data, SchemaFileLocation)
+	if err != nil {
+		return err
+	}
+
+	cmd.Printf("AuroraConfig schema updated to version %s\n", schema.Version)
+	return nil
+}
+
+// readSchema reads a schema from a http(s) URL or a local file
+func readSchema(source string) ([]byte, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		return ioutil.ReadFile(source)
+	}
+
+	client := http.Client{Timeout: 30 * time.Second}
+	response, err := client.Get(source)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("got status %s", response.Status)
+	}
+	return ioutil.ReadAll(response.Body)
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_updateSchema(t *testing.T) {
+	folder := t.TempDir()
+	SchemaFileLocation = filepath.Join(folder, "schema.json")
+	defer func() { SchemaFileLocation = "" }()
+	schema := `{"version": "99", "fields": {"replicas": {"types": ["int"]}}}`
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/schema.json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(schema))
+	}))
+	defer ts.Close()
+
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+
+	assert.NoError(t, updateSchema(cmd, ts.URL+"/schema.json"))
+	assert.Equal(t, "AuroraConfig schema updated to version 99\n", out.String())
+	assert.Equal(t, "99", auroraconfig.LoadSchema(SchemaFileLocation).Version)
+
+	err := updateSchema(cmd, ts.URL+"/missing.json")
+	assert.EqualError(t, err, "Could not read AuroraConfig schema from "+ts.URL+"/missing.json: got status 404 Not Found")
+
+	local := filepath.Join(folder, "local.json")
+	assert.NoError(t, ioutil.WriteFile(local, []byte(`{"version": "100"}`), 0644))
+	assert.EqualError(t, updateSchema(cmd, local), "AuroraConfig schema must have a version and fields")
+	assert.Equal(t, "99", auroraconfig.LoadSchema(SchemaFileLocation).Version)
+}
@@ -16,11 +16,13 @@ import (
 	"github.com/skatteetaten/ao/pkg/client"
 	"github.com/skatteetaten/ao/pkg/editor"
 	"github.com/skatteetaten/ao/pkg/prompt"
+	"github.com/skatteetaten/ao/pkg/secretfile"
 	"github.com/spf13/cobra"
 )
 
 var (
-	flagOnlyVaults bool
+	flagOnlyVaults    bool
+	flagVaultIdentity string
 
 	errNoPermissionsSpecified = errors.New("No permission groups was specified")
 	errEmptyGroups            = errors.New("Cannot find groups in permissions")
@@ -120,6 +122,7 @@ func init() {
 	vaultCmd.AddCommand(vaultRenameSecretCmd)
 	vaultCmd.AddCommand(vaultGetSecretCmd)
 
+	vaultCmd.PersistentFlags().StringVar(&flagVaultIdentity, "identity", "", "age identity file to decrypt secret files ending with .age, defaults to $"+secretfile.AgeIdentityEnv)
 	vaultGetCmd.Flags().BoolVarP(&flagAsList, "list", "", false, "print vault/secret as a list")
 	vaultGetCmd.Flags().BoolVarP(&flagOnlyVaults, "only-vaults", "", false, "print vaults as a list")
 }
@@ -197,7 +200,16 @@ func RenameVault(cmd *cobra.Command, args []string) error {
 		return cmd.Usage()
 	}
 
-	err := DefaultAPIClient.RenameVault(args[0], args[1])
+	inUse, err := warnIfVaultInUse(cmd, args[0])
+	if err != nil {
+		return err
+	}
+	message := fmt.Sprintf("Do you want to rename vault %s to %s, and update the applications yourself?", args[0], args[1])
+	if inUse && !prompt.Confirm(message, false) {
+		return errors.New("Did not rename vault " + args[0])
+	}
+
+	err = DefaultAPIClient.RenameVault(args[0], args[1])
 	if err != nil {
 		return err
 	}
@@ -316,10 +328,14 @@ func DeleteVault(cmd *cobra.Command, args []string) error {
 		return cmd.Usage()
 	}
 
+	if _, err := warnIfVaultInUse(cmd, args[0]); err != nil {
+		return err
+	}
+
 	message := fmt.Sprintf("Do you want to delete vault %s in affiliation %s?", args[0], AOSession.AuroraConfig)
 	shouldDelete := prompt.Confirm(message, false)
 	if !shouldDelete {
-		return nil
+		return errors.New("Did not delete vault " + args[0])
 	}
 
 	err := DefaultAPIClient.DeleteVault(args[0])
@@ -450,7 +466,7 @@ func collectSecrets(filePath string) ([]client.Secret, error) {
 		if err != nil {
 			return nil, err
 		}
-		secret := client.NewSecret(f.Name(), base64.StdEncoding.EncodeToString([]byte(content)))
+		secret := client.NewSecret(secretfile.SecretName(f.Name()), base64.StdEncoding.EncodeToString([]byte(content)))
 
 		secrets = append(secrets, secret)
 	}
@@ -495,7 +511,7 @@ func collectVaultSecrets(filePath string, vault *client.Vault, includePermission
 			if err != nil {
 				return err
 			}
-			secret := client.NewSecret(f.Name(), base64.StdEncoding.EncodeToString([]byte(content)))
+			secret := client.NewSecret(secretfile.SecretName(f.Name()), base64.StdEncoding.EncodeToString([]byte(content)))
 			vault.AddSecret(secret)
 		}
 	}
@@ -503,12 +519,18 @@ func collectVaultSecrets(filePath string, vault *client.Vault, includePermission
 	return nil
 }
 
+// readSecretFile reads a secret file, and decrypts it if it is encrypted
 func readSecretFile(fileName string) (string, error) {
 	data, err := ioutil.ReadFile(fileName)
 	if err != nil {
 		return "", err
 	}
 
+	data, err = secretfile.Decrypt(fileName, data, flagVaultIdentity)
+	if err != nil {
+		return "", err
+	}
+
 	return string(data), nil
 }
 
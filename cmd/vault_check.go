@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/client"
+	"github.com/skatteetaten/ao/pkg/deploymentspec"
+	"github.com/skatteetaten/ao/pkg/properties"
+	"github.com/spf13/cobra"
+)
+
+const vaultCheckLong = `Check the vaults referred to by secretVault and secretVaults in the deployment specs of the applications.
+Vaults that do not exist or that you do not have access to, and secrets and keys missing in the vaults,
+are reported, as are applications with invalid deploy specs. When all applications are checked, and all deploy specs
+are valid, vaults not referred to by any application are reported as unused.
+The command fails if any problems are found, so it can be used in a build pipeline.`
+
+const vaultCheckExample = `  # Check the vault references of all applications
+  ao vault check
+
+  # Check the applications in the environment prod running in cluster utv04
+  ao vault check prod --selector cluster=utv04`
+
+var vaultCheckCmd = &cobra.Command{
+	Use:     "check [applicationDeploymentRef]",
+	Short:   "Check that the vaults, secrets and keys used by applications exist",
+	Long:    vaultCheckLong,
+	Example: vaultCheckExample,
+	RunE:    CheckVaults,
+}
+
+func init() {
+	vaultCmd.AddCommand(vaultCheckCmd)
+	addSelectorFlag(vaultCheckCmd)
+}
+
+// CheckVaults is the entry point of the `vault check` cli command
+func CheckVaults(cmd *cobra.Command, args []string) error {
+	if len(args) > 1 {
+		return cmd.Usage()
+	}
+	var search string
+	if len(args) == 1 {
+		search = args[0]
+	}
+
+	applications, err := getApplications(DefaultAPIClient, search, nil)
+	if err != nil {
+		return err
+	} else if len(applications) == 0 {
+		return errors.New("No applications to check")
+	}
+	specs, invalid, err := getDeploySpecs(DefaultAPIClient, applications)
+	if err != nil {
+		return err
+	}
+	specs, err = selectDeploymentSpecs(specs)
+	if err != nil {
+		return err
+	}
+
+	vaults, err := DefaultAPIClient.GetVaults()
+	if err != nil {
+		return err
+	}
+
+	// Vaults may be used by the applications with invalid deploy specs, so they are only reported as unused without them
+	rows, err := checkVaultReferences(specs, vaults, getSecretContent, search == "" && flagSelector == "" && len(invalid) == 0)
+	if err != nil {
+		return err
+	}
+	rows = append(invalidDeploySpecRows(invalid), rows...)
+	if len(rows) == 0 {
+		cmd.Printf("The vault references of %d application(s) are valid\n", len(specs))
+		return nil
+	}
+
+	DefaultTablePrinter("APPLICATIONDEPLOYMENTREF\tFIELD\tVAULT\tPROBLEM", rows, cmd.OutOrStdout())
+	return errors.Errorf("Found %d problem(s) with vaults", len(rows))
+}
+
+func getSecretContent(vaultName, secretName string) (string, error) {
+	secret, err := DefaultAPIClient.GetSecret(vaultName, secretName)
+	if err != nil {
+		return "", err
+	}
+	return secret.DecodedSecret()
+}
+
+// checkVaultReferences returns a row for each problem with the vault references of specs.
+// Vaults not referred to by any of the specs are reported if reportUnused is set.
+func checkVaultReferences(specs []deploymentspec.DeploymentSpec, vaults []client.Vault, getSecret func(vaultName, secretName string) (string, error), reportUnused bool) ([]string, error) {
+	vaultsByName := make(map[string]client.Vault)
+	for _, vault := range vaults {
+		vaultsByName[vault.Name] = vault
+	}
+	used := make(map[string]bool)
+	contents := make(map[string]string)
+	getContent := func(vaultName, secretName string) (string, error) {
+		name := vaultName + "/" + secretName
+		if content, ok := contents[name]; ok {
+			return content, nil
+		}
+		content, err := getSecret(vaultName, secretName)
+		if err != nil {
+			return "", err
+		}
+		contents[name] = content
+		return content, nil
+	}
+
+	var rows []string
+	for _, spec := range specs {
+		application := spec.GetString("applicationDeploymentRef")
+		for _, reference := range spec.VaultReferences() {
+			used[reference.Vault] = true
+			problems, err := checkVaultReference(reference, vaultsByName, getContent)
+			if err != nil {
+				return nil, err
+			}
+			for _, problem := range problems {
+				rows = append(rows, fmt.Sprintf("%s\t%s\t%s\t%s", application, reference.Field, reference.Vault, problem))
+			}
+		}
+	}
+
+	if reportUnused {
+		var unused []string
+		for name := range vaultsByName {
+			if !used[name] {
+				unused = append(unused, fmt.Sprintf("-\t-\t%s\tunused", name))
+			}
+		}
+		sort.Strings(unused)
+		rows = append(rows, unused...)
+	}
+	return rows, nil
+}
+
+// invalidDeploySpecRows returns a row for each application with an invalid deploy spec, whose vault references can not be checked
+func invalidDeploySpecRows(invalid map[string]error) []string {
+	var rows []string
+	for _, ref := range sortedKeys(invalid) {
+		rows = append(rows, fmt.Sprintf("%s\t-\t-\tinvalid deploy spec: %s", ref, oneLine(invalid[ref])))
+	}
+	return rows
+}
+
+func checkVaultReference(reference deploymentspec.VaultReference, vaults map[string]client.Vault, getContent func(vaultName, secretName string) (string, error)) ([]string, error) {
+	vault, exists := vaults[reference.Vault]
+	if !exists {
+		return []string{"missing vault"}, nil
+	}
+	if !vault.HasAccess {
+		return []string{fmt.Sprintf("no access, the vault has the permissions %s", strings.Join(vault.Permissions, " "))}, nil
+	}
+
+	var secretNames []string
+	for _, secret := range vault.Secrets {
+		if reference.File == "" && strings.HasSuffix(secret.Name, ".properties") {
+			secretNames = append(secretNames, secret.Name)
+		} else if secret.Name == reference.File {
+			secretNames = []string{secret.Name}
+		}
+	}
+	if reference.File != "" && len(secretNames) == 0 {
+		return []string{"missing secret " + reference.File}, nil
+	}
+
+	var contents []string
+	if len(reference.Keys) > 0 {
+		for _, secretName := range secretNames {
+			content, err := getContent(reference.Vault, secretName)
+			if err != nil {
+				return nil, err
+			}
+			contents = append(contents, content)
+		}
+	}
+
+	location := strings.Join(secretNames, ", ")
+	if location == "" {
+		location = "any properties secret"
+	}
+	var problems []string
+	for _, key := range reference.Keys {
+		found := false
+		for _, content := range contents {
+			if _, ok := properties.Get(content, key); ok {
+				found = true
+				break
+			}
+		}
+		if !found {
+			problems = append(problems, fmt.Sprintf("missing key %s in %s", key, location))
+		}
+	}
+	return problems, nil
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/client"
+	"github.com/skatteetaten/ao/pkg/deploymentspec"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_checkVaultReferences(t *testing.T) {
+	value := func(v interface{}) map[string]interface{} {
+		return map[string]interface{}{"value": v}
+	}
+	legacy := deploymentspec.NewDeploymentSpec("legacy", "dev", "utv", "1")
+	legacy["secretVault"] = map[string]interface{}{
+		"name": value("foo"),
+		"keys": value([]interface{}{"FOO", "MISSING"}),
+	}
+	app := deploymentspec.NewDeploymentSpec("app", "dev", "utv", "1")
+	app["secretVaults"] = map[string]interface{}{
+		"foo":    map[string]interface{}{"file": value("other.properties")},
+		"closed": map[string]interface{}{},
+		"gone":   map[string]interface{}{},
+	}
+
+	vaults := []client.Vault{
+		{Name: "foo", HasAccess: true, Permissions: []string{"devops"}, Secrets: []client.Secret{{Name: "latest.properties"}, {Name: "cert.pem"}}},
+		{Name: "closed", HasAccess: false, Permissions: []string{"ops", "security"}},
+		{Name: "unused", HasAccess: true},
+	}
+	getSecret := func(vaultName, secretName string) (string, error) {
+		if vaultName == "foo" && secretName == "latest.properties" {
+			return "FOO=bar\n", nil
+		}
+		return "", errors.Errorf("unexpected secret %s/%s", vaultName, secretName)
+	}
+
+	rows, err := checkVaultReferences([]deploymentspec.DeploymentSpec{legacy, app}, vaults, getSecret, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"dev/legacy\tsecretVault\tfoo\tmissing key MISSING in latest.properties",
+		"dev/app\tsecretVaults/closed\tclosed\tno access, the vault has the permissions ops security",
+		"dev/app\tsecretVaults/foo\tfoo\tmissing secret other.properties",
+		"dev/app\tsecretVaults/gone\tgone\tmissing vault",
+		"-\t-\tunused\tunused",
+	}, rows)
+
+	rows, err = checkVaultReferences([]deploymentspec.DeploymentSpec{legacy}, vaults, getSecret, false)
+	assert.NoError(t, err)
+	assert.Len(t, rows, 1)
+}
+
+type deploySpecClientStub struct {
+	client.DeploySpecClientMock
+	invalid map[string]bool
+}
+
+// GetAuroraDeploySpec fails if any of the applications are invalid
+func (api *deploySpecClientStub) GetAuroraDeploySpec(applications []string, defaults bool, ignoreErrors bool) ([]deploymentspec.DeploymentSpec, error) {
+	var specs []deploymentspec.DeploymentSpec
+	for _, application := range applications {
+		if api.invalid[application] {
+			return nil, errors.Errorf("Application: %s\nField:       version (Missing)", application)
+		}
+		parts := strings.Split(application, "/")
+		specs = append(specs, deploymentspec.NewDeploymentSpec(parts[1], parts[0], "utv", "1"))
+	}
+	return specs, nil
+}
+
+func Test_getDeploySpecs(t *testing.T) {
+	apiClient := &deploySpecClientStub{invalid: map[string]bool{"dev/broken": true}}
+
+	specs, invalid, err := getDeploySpecs(apiClient, []string{"dev/app", "dev/other"})
+	assert.NoError(t, err)
+	assert.Len(t, specs, 2)
+	assert.Empty(t, invalid)
+
+	specs, invalid, err = getDeploySpecs(apiClient, []string{"dev/app", "dev/broken"})
+	assert.NoError(t, err)
+	assert.Len(t, specs, 1)
+	assert.Equal(t, "dev/app", specs[0].GetString("applicationDeploymentRef"))
+	assert.Equal(t, []string{"dev/broken\t-\t-\tinvalid deploy spec: Application: dev/broken Field: version (Missing)"}, invalidDeploySpecRows(invalid))
+
+	_, _, err = getDeploySpecs(apiClient, []string{"dev/broken"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/client"
+	"github.com/skatteetaten/ao/pkg/secretfile"
+	"github.com/spf13/cobra"
+)
+
+// vaultPermissionsFile is the name of the file holding the permissions of an exported vault
+const vaultPermissionsFile = "permissions"
+
+const vaultExportLong = `Export vaults to a folder, with one folder per vault. Each secret is written to a file,
+and the permissions of the vault to a file named permissions, in the format read by 'ao vault create'
+and 'ao vault import'. Use --auroraconfig to export from another AuroraConfig than the one logged in to.
+With --encrypt-to, each secret is encrypted to the given age or OpenPGP recipients, and its file name gets
+the extension .age or .gpg. The keys of OpenPGP recipients must be valid in the local gpg keyring, that is
+signed by you or someone you trust. The vault commands that read secret files decrypt such files with the local
+age identity given by --identity, or the keys in the local gpg keyring.`
+
+const vaultImportLong = `Create vaults from a folder written by 'ao vault export'. Each folder in the given folder is
+created as a vault with the name of the folder. A folder without sub folders is created as a single vault.
+None of the vaults can exist in advance. Use --auroraconfig to import into another AuroraConfig than
+the one logged in to.`
+
+const vaultExportExample = `  # Back up all vaults
+  ao vault export --all vaults-backup
+
+  # Move the vault foo from the AuroraConfig paas to the AuroraConfig sales
+  ao vault export foo moved --auroraconfig paas
+  ao vault import moved --auroraconfig sales
+
+  # Back up all vaults encrypted with age, and restore them
+  ao vault export --all vaults-backup --encrypt-to age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p
+  ao vault import vaults-backup --identity ~/.config/age/keys.txt`
+
+var (
+	flagVaultExportAll       bool
+	flagVaultExportRecipient []string
+)
+
+var (
+	vaultExportCmd = &cobra.Command{
+		Use:     "export <vaultname|--all> <folder>",
+		Short:   "Export vaults with secrets and permissions to a folder",
+		Long:    vaultExportLong,
+		Example: vaultExportExample,
+		RunE:    ExportVaults,
+	}
+
+	vaultImportCmd = &cobra.Command{
+		Use:     "import <folder>",
+		Short:   "Create vaults from a folder written by 'ao vault export'",
+		Long:    vaultImportLong,
+		Example: vaultExportExample,
+		RunE:    ImportVaults,
+	}
+)
+
+func init() {
+	vaultCmd.AddCommand(vaultExportCmd)
+	vaultCmd.AddCommand(vaultImportCmd)
+
+	vaultExportCmd.Flags().BoolVar(&flagVaultExportAll, "all", false, "export all vaults")
+	vaultExportCmd.Flags().StringArrayVar(&flagVaultExportRecipient, "encrypt-to", []string{}, "age public key, or OpenPGP key id or email, to encrypt secrets to")
+	vaultExportCmd.Flags().StringVarP(&flagAuroraConfig, "auroraconfig", "a", "", "AuroraConfig to export vaults from")
+	vaultImportCmd.Flags().StringVarP(&flagAuroraConfig, "auroraconfig", "a", "", "AuroraConfig to import vaults into")
+}
+
+// ExportVaults is the entry point of the `vault export` cli command
+func ExportVaults(cmd *cobra.Command, args []string) error {
+	if (flagVaultExportAll && len(args) != 1) || (!flagVaultExportAll && len(args) != 2) {
+		return cmd.Usage()
+	}
+	folder := args[len(args)-1]
+
+	apiClient, err := getVaultAPIClient()
+	if err != nil {
+		return err
+	}
+	vaults, err := apiClient.GetVaults()
+	if err != nil {
+		return err
+	}
+	if !flagVaultExportAll {
+		vaults, err = findVault(vaults, args[0])
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, vault := range vaults {
+		if !vault.HasAccess {
+			cmd.Printf("Skipped vault %s, you do not have access to it\n", vault.Name)
+			continue
+		}
+		vault, err := getVaultSecrets(apiClient, vault)
+		if err != nil {
+			return err
+		}
+		if err := writeVaultFolder(folder, vault, flagVaultExportRecipient); err != nil {
+			return err
+		}
+		cmd.Printf("Vault %s exported to %s\n", vault.Name, path.Join(folder, vault.Name))
+	}
+	return nil
+}
+
+// ImportVaults is the entry point of the `vault import` cli command
+func ImportVaults(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return cmd.Usage()
+	}
+
+	vaults, err := readVaultFolders(args[0])
+	if err != nil {
+		return err
+	}
+
+	apiClient, err := getVaultAPIClient()
+	if err != nil {
+		return err
+	}
+	existing, err := apiClient.GetVaults()
+	if err != nil {
+		return err
+	}
+	for _, vault := range vaults {
+		if _, err := findVault(existing, vault.Name); err == nil {
+			return errors.Errorf("Vault %s already exists in %s", vault.Name, apiClient.Affiliation)
+		}
+	}
+
+	for _, vault := range vaults {
+		if err := apiClient.CreateVault(*vault); err != nil {
+			return errors.Wrapf(err, "Failed to create vault %s", vault.Name)
+		}
+		cmd.Printf("Vault %s imported with %d secret(s)\n", vault.Name, len(vault.Secrets))
+	}
+	return nil
+}
+
+func getVaultAPIClient() (*client.APIClient, error) {
+	if flagAuroraConfig == "" {
+		return DefaultAPIClient, nil
+	}
+	return getAPIClient(flagAuroraConfig, pFlagToken, "")
+}
+
+// getVaultSecrets returns vault with the contents of its secrets, which are left out when listing vaults
+func getVaultSecrets(apiClient *client.APIClient, vault client.Vault) (client.Vault, error) {
+	var secrets []client.Secret
+	for _, secret := range vault.Secrets {
+		content, err := apiClient.GetSecret(vault.Name, secret.Name)
+		if err != nil {
+			return vault, err
+		}
+		secrets = append(secrets, *content)
+	}
+	vault.Secrets = secrets
+	return vault, nil
+}
+
+func findVault(vaults []client.Vault, name string) ([]client.Vault, error) {
+	for _, vault := range vaults {
+		if vault.Name == name {
+			return []client.Vault{vault}, nil
+		}
+	}
+	return nil, errors.Errorf("Could not find vault %s", name)
+}
+
+// writeVaultFolder writes the secrets and permissions of vault to a new folder in folder.
+// The secrets are encrypted if there are any recipients.
+func writeVaultFolder(folder string, vault client.Vault, recipients []string) error {
+	vaultFolder := path.Join(folder, vault.Name)
+	if files, err := ioutil.ReadDir(vaultFolder); err == nil && len(files) > 0 {
+		return errors.Errorf("%s already exists and is not empty", vaultFolder)
+	}
+	if err := os.MkdirAll(vaultFolder, 0700); err != nil {
+		return err
+	}
+
+	for _, secret := range vault.Secrets {
+		if strings.Contains(secret.Name, "permission") {
+			return errors.Errorf("Secret %s/%s can not be exported, since files with permission in the name are read as permissions", vault.Name, secret.Name)
+		}
+		content, err := base64.StdEncoding.DecodeString(secret.Base64Content)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to decode secret %s/%s", vault.Name, secret.Name)
+		}
+		fileName := secret.Name
+		if len(recipients) > 0 {
+			var extension string
+			content, extension, err = secretfile.Encrypt(content, recipients)
+			if err != nil {
+				return errors.Wrapf(err, "Failed to encrypt secret %s/%s", vault.Name, secret.Name)
+			}
+			fileName += extension
+		}
+		if err := ioutil.WriteFile(path.Join(vaultFolder, fileName), content, 0600); err != nil {
+			return err
+		}
+	}
+
+	permissions, err := json.MarshalIndent(struct {
+		Groups []string `json:"groups"`
+	}{vault.Permissions}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path.Join(vaultFolder, vaultPermissionsFile), permissions, 0600)
+}
+
+// readVaultFolders reads a vault from each folder in folder, or from folder itself if it has no folders
+func readVaultFolders(folder string) ([]*client.Vault, error) {
+	files, err := ioutil.ReadDir(folder)
+	if err != nil {
+		return nil, err
+	}
+
+	var vaultFolders []string
+	for _, file := range files {
+		if file.IsDir() {
+			vaultFolders = append(vaultFolders, path.Join(folder, file.Name()))
+		}
+	}
+	if len(vaultFolders) == 0 {
+		vaultFolders = []string{folder}
+	}
+	sort.Strings(vaultFolders)
+
+	var vaults []*client.Vault
+	for _, vaultFolder := range vaultFolders {
+		vault := client.NewVault(path.Base(vaultFolder))
+		if err := collectVaultSecrets(vaultFolder, vault, true); err != nil {
+			return nil, errors.Wrapf(err, "Failed to read vault %s", vault.Name)
+		}
+		if len(vault.Permissions) == 0 {
+			return nil, errors.Errorf("Vault %s has no %s file", vault.Name, vaultPermissionsFile)
+		}
+		vaults = append(vaults, vault)
+	}
+	return vaults, nil
+}
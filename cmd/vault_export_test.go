@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"testing"
+
+	"github.com/skatteetaten/ao/pkg/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_writeAndReadVaultFolders(t *testing.T) {
+	folder, err := ioutil.TempDir("", "ao_vault_export_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(folder)
+
+	foo := client.NewVault("foo")
+	foo.Permissions = []string{"devops", "system:serviceaccount:paas:deployer"}
+	foo.AddSecret(client.NewSecret("latest.properties", base64.StdEncoding.EncodeToString([]byte("FOO=BAR\n"))))
+	foo.AddSecret(client.NewSecret("key.bin", base64.StdEncoding.EncodeToString([]byte{0, 1, 2})))
+	bar := client.NewVault("bar")
+	bar.Permissions = []string{"devops"}
+	bar.AddSecret(client.NewSecret("latest.properties", base64.StdEncoding.EncodeToString([]byte("BAR=BAZ"))))
+
+	assert.NoError(t, writeVaultFolder(folder, *foo, nil))
+	assert.NoError(t, writeVaultFolder(folder, *bar, nil))
+	assert.EqualError(t, writeVaultFolder(folder, *bar, nil), path.Join(folder, "bar")+" already exists and is not empty")
+
+	vaults, err := readVaultFolders(folder)
+	assert.NoError(t, err)
+	assert.Len(t, vaults, 2)
+	assert.Equal(t, bar, vaults[0])
+	assert.Equal(t, "foo", vaults[1].Name)
+	assert.Equal(t, foo.Permissions, vaults[1].Permissions)
+	assert.ElementsMatch(t, foo.Secrets, vaults[1].Secrets)
+
+	vaults, err = readVaultFolders(path.Join(folder, "bar"))
+	assert.NoError(t, err)
+	assert.Equal(t, []*client.Vault{bar}, vaults)
+}
+
+func Test_writeVaultFolderWithPermissionSecret(t *testing.T) {
+	folder, err := ioutil.TempDir("", "ao_vault_export_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(folder)
+
+	vault := client.NewVault("foo")
+	vault.AddSecret(client.NewSecret("permissions.properties", ""))
+
+	assert.EqualError(t, writeVaultFolder(folder, *vault, nil), "Secret foo/permissions.properties can not be exported, since files with permission in the name are read as permissions")
+}
+
+func Test_writeAndReadEncryptedVaultFolder(t *testing.T) {
+	if _, err := exec.LookPath("gpg"); err != nil {
+		t.Skip("gpg is not installed")
+	}
+	folder, err := ioutil.TempDir("", "ao_vault_export_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(folder)
+	os.Setenv("GNUPGHOME", folder)
+	defer os.Unsetenv("GNUPGHOME")
+	defer exec.Command("gpgconf", "--kill", "gpg-agent").Run()
+	if err := exec.Command("gpg", "--batch", "--passphrase", "", "--quick-gen-key", "ao-test@example.com", "default", "default", "never").Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	vault := client.NewVault("foo")
+	vault.Permissions = []string{"devops"}
+	vault.AddSecret(client.NewSecret("latest.properties", base64.StdEncoding.EncodeToString([]byte("FOO=BAR"))))
+
+	assert.NoError(t, writeVaultFolder(folder, *vault, []string{"ao-test@example.com"}))
+	encrypted, err := ioutil.ReadFile(path.Join(folder, "foo", "latest.properties.gpg"))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(encrypted), "FOO=BAR")
+
+	vaults, err := readVaultFolders(path.Join(folder, "foo"))
+	assert.NoError(t, err)
+	assert.Equal(t, []*client.Vault{vault}, vaults)
+}
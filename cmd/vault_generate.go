@@ -0,0 +1,274 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/properties"
+	"github.com/skatteetaten/ao/pkg/secretfile"
+	"github.com/skatteetaten/ao/pkg/service"
+	"github.com/spf13/cobra"
+)
+
+const vaultRotateLong = `Replace the value of a key in a properties secret with a new random value.
+Before the secret is updated, the previous value is backed up to a file encrypted to the --encrypt-to
+recipients, in the folder given by --backup-dir. With --redeploy, every deployed application referring
+to the vault in its deployment spec is redeployed to read the new value. Applications that are not
+deployed are not started.`
+
+const vaultGenerateExample = `  # Set db.password in foo/latest.properties to 32 random letters and digits
+  ao vault generate foo/latest.properties db.password
+
+  # Use 64 random hex digits
+  ao vault generate foo/latest.properties api.key --length 64 --charset hex
+
+  # Replace db.password, back up the previous value encrypted with gpg, and redeploy the applications using foo
+  ao vault rotate foo/latest.properties db.password --encrypt-to ops@example.com --redeploy`
+
+// secretCharsets are the named character sets for generated values
+var secretCharsets = map[string]string{
+	"alphanumeric": "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789",
+	"hex":          "0123456789abcdef",
+	"base64url":    "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_",
+	"ascii":        "!\"#$%&'()*+,-./0123456789:;<=>?@ABCDEFGHIJKLMNOPQRSTUVWXYZ[\\]^_`abcdefghijklmnopqrstuvwxyz{|}~",
+}
+
+var (
+	flagVaultGenerateLength   int
+	flagVaultGenerateCharset  string
+	flagVaultRotateRecipients []string
+	flagVaultRotateBackupDir  string
+	flagVaultRotateRedeploy   bool
+	flagVaultRotateYes        bool
+)
+
+var (
+	vaultGenerateCmd = &cobra.Command{
+		Use:     "generate <vaultname/secret> <key>",
+		Short:   "Set a new key in a properties secret to a random value",
+		Example: vaultGenerateExample,
+		RunE:    GenerateSecretKey,
+	}
+
+	vaultRotateCmd = &cobra.Command{
+		Use:     "rotate <vaultname/secret> <key>",
+		Short:   "Replace the value of a key in a properties secret with a random value",
+		Long:    vaultRotateLong,
+		Example: vaultGenerateExample,
+		RunE:    RotateSecretKey,
+	}
+)
+
+func init() {
+	vaultCmd.AddCommand(vaultGenerateCmd)
+	vaultCmd.AddCommand(vaultRotateCmd)
+
+	for _, command := range []*cobra.Command{vaultGenerateCmd, vaultRotateCmd} {
+		command.Flags().IntVar(&flagVaultGenerateLength, "length", 32, "length of the generated value")
+		command.Flags().StringVar(&flagVaultGenerateCharset, "charset", "alphanumeric", "characters of the generated value, one of "+strings.Join(getSecretCharsetNames(), ", ")+", or the characters to use")
+	}
+	vaultRotateCmd.Flags().StringArrayVar(&flagVaultRotateRecipients, "encrypt-to", []string{}, "age public key, or OpenPGP key id or email, to encrypt the backup of the previous value to")
+	vaultRotateCmd.Flags().StringVar(&flagVaultRotateBackupDir, "backup-dir", "", "folder for backups of previous values, defaults to ~/.ao-vault-backups")
+	vaultRotateCmd.Flags().BoolVar(&flagVaultRotateRedeploy, "redeploy", false, "redeploy the applications using the vault")
+	vaultRotateCmd.Flags().BoolVarP(&flagVaultRotateYes, "yes", "y", false, "Suppress prompts and accept redeploy")
+}
+
+// GenerateSecretKey is the entry point of the `vault generate` cli command
+func GenerateSecretKey(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return cmd.Usage()
+	}
+	key := args[1]
+
+	value, err := generateSecretValue(flagVaultGenerateLength, flagVaultGenerateCharset)
+	if err != nil {
+		return err
+	}
+	vaultName, secretName, content, err := getDecodedSecret(args[0])
+	if err != nil {
+		return err
+	}
+	if _, exists := properties.Get(content, key); exists {
+		return errors.Errorf("Key %s already exists in secret %s, use 'ao vault rotate' to replace it", key, args[0])
+	}
+
+	if err := DefaultAPIClient.UpdateSecret(vaultName, secretName, properties.Set(content, key, value)); err != nil {
+		return err
+	}
+
+	cmd.Printf("Key %s in secret %s has been set to a random value\n", key, args[0])
+	return nil
+}
+
+// RotateSecretKey is the entry point of the `vault rotate` cli command
+func RotateSecretKey(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return cmd.Usage()
+	}
+	key := args[1]
+	if len(flagVaultRotateRecipients) == 0 {
+		return errors.New("--encrypt-to is required to back up the previous value")
+	}
+
+	value, err := generateSecretValue(flagVaultGenerateLength, flagVaultGenerateCharset)
+	if err != nil {
+		return err
+	}
+	vaultName, secretName, content, err := getDecodedSecret(args[0])
+	if err != nil {
+		return err
+	}
+	previous, exists := properties.Get(content, key)
+	if !exists {
+		return errors.Errorf("Could not find key %s in secret %s, use 'ao vault generate' to create it", key, args[0])
+	}
+
+	backupDir := flagVaultRotateBackupDir
+	if backupDir == "" {
+		home, err := homedir.Dir()
+		if err != nil {
+			return err
+		}
+		backupDir = filepath.Join(home, ".ao-vault-backups")
+	}
+	backup, err := backupSecretValue(backupDir, vaultName, secretName, key, previous, flagVaultRotateRecipients, time.Now())
+	if err != nil {
+		return err
+	}
+	cmd.Printf("The previous value of %s has been backed up to %s\n", key, backup)
+
+	if err := DefaultAPIClient.UpdateSecret(vaultName, secretName, properties.Set(content, key, value)); err != nil {
+		return err
+	}
+	cmd.Printf("Key %s in secret %s has been rotated\n", key, args[0])
+
+	if !flagVaultRotateRedeploy {
+		return nil
+	}
+	specs, invalid, err := getAllDeploySpecs()
+	if err != nil {
+		return err
+	}
+	if len(invalid) > 0 {
+		cmd.Printf("WARNING: %d application(s) with invalid deploy specs are not redeployed, even if they use vault %s:\n", len(invalid), vaultName)
+		for _, ref := range sortedKeys(invalid) {
+			cmd.Printf("  %s: %s\n", ref, oneLine(invalid[ref]))
+		}
+	}
+	applications := findVaultApplications(specs, vaultName)
+	if len(applications) == 0 {
+		cmd.Printf("Vault %s is not used by any application, nothing to redeploy\n", vaultName)
+		return nil
+	}
+	return redeployVaultApplications(cmd, vaultName, applications)
+}
+
+// redeployVaultApplications redeploys the applications that are deployed, leaving stopped applications as they are
+func redeployVaultApplications(cmd *cobra.Command, vaultName string, applications []string) error {
+	specs, err := service.GetFilteredDeploymentSpecs(DefaultAPIClient, applications, "")
+	if err != nil {
+		return err
+	}
+	activeDeploymentSpecs, err := getDeployedDeploymentSpecs(getApplicationDeploymentClient, specs, AOSession.AuroraConfig, pFlagToken)
+	if err != nil {
+		return err
+	} else if len(activeDeploymentSpecs) == 0 {
+		cmd.Printf("None of the applications using vault %s are deployed, nothing to redeploy\n", vaultName)
+		return nil
+	}
+
+	partitions, err := createDeploySpecPartitions(AOSession.AuroraConfig, pFlagToken, AOConfig.Clusters, activeDeploymentSpecs)
+	if err != nil {
+		return err
+	}
+	if !getRedeployConfirmation(flagVaultRotateYes, activeDeploymentSpecs, cmd.OutOrStdout()) {
+		return errors.New("No applications to redeploy")
+	}
+
+	result, unsuccessfulErr := deployToReachableClusters(getApplicationDeploymentClient, partitions, make(map[string]string))
+	printDeployResult(result, cmd.OutOrStdout())
+	return unsuccessfulErr
+}
+
+// generateSecretValue returns a random value of length characters from a named charset, or the characters in charset
+func generateSecretValue(length int, charset string) (string, error) {
+	if named, ok := secretCharsets[charset]; ok {
+		charset = named
+	}
+	characters := []rune(charset)
+	if len(characters) < 2 {
+		return "", errors.Errorf("The charset must be one of %s, or at least two characters", strings.Join(getSecretCharsetNames(), ", "))
+	}
+	if length < 1 {
+		return "", errors.New("The length must be at least 1")
+	}
+
+	value := make([]rune, length)
+	count := big.NewInt(int64(len(characters)))
+	for i := range value {
+		index, err := rand.Int(rand.Reader, count)
+		if err != nil {
+			return "", err
+		}
+		value[i] = characters[index.Int64()]
+	}
+	return string(value), nil
+}
+
+// backupSecretValue writes key=value to a new encrypted properties file in folder, and returns its path
+func backupSecretValue(folder, vaultName, secretName, key, value string, recipients []string, now time.Time) (string, error) {
+	encrypted, extension, err := secretfile.Encrypt([]byte(properties.Set("", key, value)), recipients)
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to encrypt the backup of the previous value")
+	}
+
+	backupFolder := filepath.Join(folder, vaultName, secretName)
+	if err := os.MkdirAll(backupFolder, 0700); err != nil {
+		return "", err
+	}
+	// Backups taken within the same second get a counter, so an existing backup is never overwritten
+	for attempt := 0; ; attempt++ {
+		backup := filepath.Join(backupFolder, backupFileName(key, now, attempt)+extension)
+		file, err := os.OpenFile(backup, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if os.IsExist(err) {
+			continue
+		} else if err != nil {
+			return "", err
+		}
+		_, err = file.Write(encrypted)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return "", err
+		}
+		return backup, nil
+	}
+}
+
+// backupFileName returns the name of the backup of key, with path separators in key replaced to keep the file in its folder.
+// The name of any attempt after the first gets the attempt as a counter after the time.
+func backupFileName(key string, now time.Time, attempt int) string {
+	name := strings.NewReplacer("/", "_", "\\", "_").Replace(key) + "." + now.UTC().Format("20060102T150405Z")
+	if attempt > 0 {
+		name += fmt.Sprintf("-%d", attempt)
+	}
+	return name + ".properties"
+}
+
+func getSecretCharsetNames() []string {
+	var names []string
+	for name := range secretCharsets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
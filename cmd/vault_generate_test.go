@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/skatteetaten/ao/pkg/secretfile"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_generateSecretValue(t *testing.T) {
+	value, err := generateSecretValue(64, "hex")
+	assert.NoError(t, err)
+	assert.Len(t, value, 64)
+	assert.Empty(t, strings.Trim(value, secretCharsets["hex"]))
+
+	value, err = generateSecretValue(10, "æø")
+	assert.NoError(t, err)
+	assert.Len(t, []rune(value), 10)
+	assert.Empty(t, strings.Trim(value, "æø"))
+
+	other, err := generateSecretValue(32, "alphanumeric")
+	assert.NoError(t, err)
+	value, err = generateSecretValue(32, "alphanumeric")
+	assert.NoError(t, err)
+	assert.NotEqual(t, other, value)
+
+	_, err = generateSecretValue(32, "x")
+	assert.EqualError(t, err, "The charset must be one of alphanumeric, ascii, base64url, hex, or at least two characters")
+
+	_, err = generateSecretValue(0, "hex")
+	assert.EqualError(t, err, "The length must be at least 1")
+}
+
+func Test_backupFileName(t *testing.T) {
+	now := time.Date(2022, 6, 1, 12, 30, 0, 0, time.UTC)
+	assert.Equal(t, "db.password.20220601T123000Z.properties", backupFileName("db.password", now, 0))
+	assert.Equal(t, "db.password.20220601T123000Z-2.properties", backupFileName("db.password", now, 2))
+	assert.Equal(t, ".._.._x.20220601T123000Z.properties", backupFileName("../../x", now, 0))
+	assert.Equal(t, "a_b_c.20220601T123000Z.properties", backupFileName("a/b\\c", now, 0))
+}
+
+func Test_backupSecretValue(t *testing.T) {
+	if _, err := exec.LookPath("gpg"); err != nil {
+		t.Skip("gpg is not installed")
+	}
+	folder, err := ioutil.TempDir("", "ao_vault_backup_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(folder)
+	os.Setenv("GNUPGHOME", folder)
+	defer os.Unsetenv("GNUPGHOME")
+	defer exec.Command("gpgconf", "--kill", "gpg-agent").Run()
+	if err := exec.Command("gpg", "--batch", "--passphrase", "", "--quick-gen-key", "ao-test@example.com", "default", "default", "never").Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2022, 6, 1, 12, 30, 0, 0, time.UTC)
+	backup, err := backupSecretValue(filepath.Join(folder, "backups"), "foo", "latest.properties", "db.password", "old", []string{"ao-test@example.com"}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(folder, "backups", "foo", "latest.properties", "db.password.20220601T123000Z.properties.gpg"), backup)
+
+	encrypted, err := ioutil.ReadFile(backup)
+	assert.NoError(t, err)
+	decrypted, err := secretfile.Decrypt(backup, encrypted, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "db.password=old\n", string(decrypted))
+
+	second, err := backupSecretValue(filepath.Join(folder, "backups"), "foo", "latest.properties", "db.password", "older", []string{"ao-test@example.com"}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(folder, "backups", "foo", "latest.properties", "db.password.20220601T123000Z-1.properties.gpg"), second)
+
+	unchanged, err := ioutil.ReadFile(backup)
+	assert.NoError(t, err)
+	assert.Equal(t, encrypted, unchanged)
+}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/properties"
+	"github.com/spf13/cobra"
+)
+
+const vaultKeyExample = `  # Print the value of db.password in the secret latest.properties in the vault foo
+  ao vault get-key foo/latest.properties db.password
+
+  # Set db.password, reading the value from standard in to keep it out of the shell history
+  pwgen -s 32 1 | ao vault set-key foo/latest.properties db.password
+
+  # Remove db.password
+  ao vault unset-key foo/latest.properties db.password`
+
+var (
+	vaultGetKeyCmd = &cobra.Command{
+		Use:     "get-key <vaultname/secret> <key>",
+		Short:   "Print the value of a key in a properties secret to standard out",
+		Example: vaultKeyExample,
+		RunE:    GetSecretKey,
+	}
+
+	vaultSetKeyCmd = &cobra.Command{
+		Use:   "set-key <vaultname/secret> <key> [value]",
+		Short: "Set the value of a key in a properties secret",
+		Long: `Set the value of a key in a properties secret, keeping the other lines as they are.
+The value is read from standard in if it is not given.`,
+		Example: vaultKeyExample,
+		RunE:    SetSecretKey,
+	}
+
+	vaultUnsetKeyCmd = &cobra.Command{
+		Use:     "unset-key <vaultname/secret> <key>",
+		Short:   "Remove a key from a properties secret",
+		Example: vaultKeyExample,
+		RunE:    UnsetSecretKey,
+	}
+)
+
+func init() {
+	vaultCmd.AddCommand(vaultGetKeyCmd)
+	vaultCmd.AddCommand(vaultSetKeyCmd)
+	vaultCmd.AddCommand(vaultUnsetKeyCmd)
+}
+
+// GetSecretKey is the entry point of the `vault get-key` cli command
+func GetSecretKey(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return cmd.Usage()
+	}
+
+	_, _, content, err := getDecodedSecret(args[0])
+	if err != nil {
+		return err
+	}
+	value, ok := properties.Get(content, args[1])
+	if !ok {
+		return errors.Errorf("Could not find key %s in secret %s", args[1], args[0])
+	}
+
+	cmd.Println(value)
+	return nil
+}
+
+// SetSecretKey is the entry point of the `vault set-key` cli command
+func SetSecretKey(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 && len(args) != 3 {
+		return cmd.Usage()
+	}
+
+	vaultName, secretName, content, err := getDecodedSecret(args[0])
+	if err != nil {
+		return err
+	}
+
+	var value string
+	if len(args) == 3 {
+		value = args[2]
+	} else {
+		data, err := ioutil.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return err
+		}
+		value = strings.TrimRight(string(data), "\r\n")
+	}
+
+	if err := DefaultAPIClient.UpdateSecret(vaultName, secretName, properties.Set(content, args[1], value)); err != nil {
+		return err
+	}
+
+	cmd.Printf("Key %s in secret %s has been set\n", args[1], args[0])
+	return nil
+}
+
+// UnsetSecretKey is the entry point of the `vault unset-key` cli command
+func UnsetSecretKey(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return cmd.Usage()
+	}
+
+	vaultName, secretName, content, err := getDecodedSecret(args[0])
+	if err != nil {
+		return err
+	}
+	changed, ok := properties.Unset(content, args[1])
+	if !ok {
+		return errors.Errorf("Could not find key %s in secret %s", args[1], args[0])
+	}
+
+	if err := DefaultAPIClient.UpdateSecret(vaultName, secretName, changed); err != nil {
+		return err
+	}
+
+	cmd.Printf("Key %s in secret %s has been removed\n", args[1], args[0])
+	return nil
+}
+
+// getDecodedSecret gets the decoded content of a secret given as vaultname/secret
+func getDecodedSecret(vaultSecret string) (string, string, string, error) {
+	split := strings.Split(vaultSecret, "/")
+	if len(split) != 2 {
+		return "", "", "", errNotValidSecretArgument
+	}
+	vaultName, secretName := split[0], split[1]
+
+	secret, err := DefaultAPIClient.GetSecret(vaultName, secretName)
+	if err != nil {
+		return "", "", "", err
+	}
+	content, err := secret.DecodedSecret()
+	if err != nil {
+		return "", "", "", err
+	}
+	return vaultName, secretName, content, nil
+}
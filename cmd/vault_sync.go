@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/client"
+	"github.com/skatteetaten/ao/pkg/prompt"
+	"github.com/spf13/cobra"
+)
+
+const vaultSyncLong = `Make a vault match a folder in the format written by 'ao vault export'.
+The secrets in the folder are compared with the secrets in the vault by content hash, and the secrets
+that are missing or changed in the vault are added or updated. The groups in the permissions file are
+added to the vault. With --prune, secrets and groups that are only in the vault are removed as well.
+All changes are shown before any of them are made. If the vault does not exist, it is created.`
+
+const vaultSyncExample = `  # Show and apply the changes needed to make the vault foo match the folder vaults/foo
+  ao vault sync foo vaults/foo
+
+  # Also remove the secrets and groups in foo that are not in vaults/foo
+  ao vault sync foo vaults/foo --prune`
+
+var (
+	flagVaultSyncPrune bool
+	flagVaultSyncYes   bool
+)
+
+var vaultSyncCmd = &cobra.Command{
+	Use:     "sync <vaultname> <folder>",
+	Short:   "Add, update and remove secrets and permissions in a vault to match a folder",
+	Long:    vaultSyncLong,
+	Example: vaultSyncExample,
+	RunE:    SyncVault,
+}
+
+// vaultSyncPlan holds the changes needed to make a vault match a folder
+type vaultSyncPlan struct {
+	create            bool
+	add               []client.Secret
+	update            []client.Secret
+	remove            []string
+	addPermissions    []string
+	removePermissions []string
+}
+
+func init() {
+	vaultCmd.AddCommand(vaultSyncCmd)
+
+	vaultSyncCmd.Flags().BoolVar(&flagVaultSyncPrune, "prune", false, "remove secrets and groups that are not in the folder")
+	vaultSyncCmd.Flags().BoolVarP(&flagVaultSyncYes, "yes", "y", false, "Suppress prompts and apply the changes")
+}
+
+// SyncVault is the entry point of the `vault sync` cli command
+func SyncVault(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return cmd.Usage()
+	}
+	vaultName, folder := args[0], args[1]
+
+	local := client.NewVault(vaultName)
+	if err := collectVaultSecrets(folder, local, true); err != nil {
+		return err
+	}
+
+	vaults, err := DefaultAPIClient.GetVaults()
+	if err != nil {
+		return err
+	}
+	var remote *client.Vault
+	if found, err := findVault(vaults, vaultName); err == nil {
+		if !found[0].HasAccess {
+			return errors.Errorf("You do not have access to vault %s", vaultName)
+		}
+		vault, err := getVaultSecrets(DefaultAPIClient, found[0])
+		if err != nil {
+			return err
+		}
+		remote = &vault
+	}
+
+	plan, err := planVaultSync(local, remote, flagVaultSyncPrune)
+	if err != nil {
+		return err
+	}
+	rows := plan.rows(vaultName)
+	if len(rows) == 0 {
+		cmd.Printf("Vault %s is in sync with %s\n", vaultName, folder)
+		return nil
+	}
+
+	DefaultTablePrinter("ACTION\tSECRET/PERMISSION", rows, cmd.OutOrStdout())
+	message := fmt.Sprintf("Do you want to make %d change(s) to vault %s in affiliation %s?", len(rows), vaultName, AOSession.AuroraConfig)
+	if !flagVaultSyncYes && !prompt.Confirm(message, false) {
+		return errors.New("Did not change vault " + vaultName)
+	}
+
+	if err := applyVaultSync(DefaultAPIClient, local, plan); err != nil {
+		return err
+	}
+	cmd.Printf("Vault %s is in sync with %s\n", vaultName, folder)
+	return nil
+}
+
+// planVaultSync compares local with remote, which is nil if the vault does not exist.
+// Secrets and permissions only in remote are removed if prune is set.
+func planVaultSync(local, remote *client.Vault, prune bool) (*vaultSyncPlan, error) {
+	if remote == nil {
+		if len(local.Permissions) == 0 {
+			return nil, errors.Errorf("Vault %s does not exist, and can not be created without a %s file", local.Name, vaultPermissionsFile)
+		}
+		return &vaultSyncPlan{create: true, add: local.Secrets, addPermissions: local.Permissions}, nil
+	}
+
+	plan := &vaultSyncPlan{}
+	remoteHashes := make(map[string][32]byte)
+	for _, secret := range remote.Secrets {
+		hash, err := hashSecret(secret)
+		if err != nil {
+			return nil, err
+		}
+		remoteHashes[secret.Name] = hash
+	}
+
+	localNames := make(map[string]bool)
+	for _, secret := range local.Secrets {
+		localNames[secret.Name] = true
+		hash, err := hashSecret(secret)
+		if err != nil {
+			return nil, err
+		}
+		remoteHash, exists := remoteHashes[secret.Name]
+		if !exists {
+			plan.add = append(plan.add, secret)
+		} else if hash != remoteHash {
+			plan.update = append(plan.update, secret)
+		}
+	}
+	if prune {
+		for _, secret := range remote.Secrets {
+			if !localNames[secret.Name] {
+				plan.remove = append(plan.remove, secret.Name)
+			}
+		}
+	}
+
+	// Without a permissions file, the permissions of the vault are left as they are
+	if len(local.Permissions) > 0 {
+		plan.addPermissions = difference(local.Permissions, remote.Permissions)
+		if prune {
+			plan.removePermissions = difference(remote.Permissions, local.Permissions)
+		}
+	}
+	return plan, nil
+}
+
+func applyVaultSync(apiClient *client.APIClient, local *client.Vault, plan *vaultSyncPlan) error {
+	if plan.create {
+		return apiClient.CreateVault(*local)
+	}
+
+	if len(plan.add) > 0 {
+		if err := apiClient.AddSecrets(local.Name, plan.add); err != nil {
+			return err
+		}
+	}
+	for _, secret := range plan.update {
+		content, err := secret.DecodedSecret()
+		if err != nil {
+			return err
+		}
+		if err := apiClient.UpdateSecret(local.Name, secret.Name, content); err != nil {
+			return err
+		}
+	}
+	if len(plan.remove) > 0 {
+		if err := apiClient.RemoveSecrets(local.Name, plan.remove); err != nil {
+			return err
+		}
+	}
+	if len(plan.addPermissions) > 0 {
+		if err := apiClient.AddPermissions(local.Name, plan.addPermissions); err != nil {
+			return err
+		}
+	}
+	if len(plan.removePermissions) > 0 {
+		if err := apiClient.RemovePermissions(local.Name, plan.removePermissions); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (plan *vaultSyncPlan) rows(vaultName string) []string {
+	var rows []string
+	if plan.create {
+		rows = append(rows, "create vault\t"+vaultName)
+	}
+	for _, secret := range plan.add {
+		rows = append(rows, "add secret\t"+secret.Name)
+	}
+	for _, secret := range plan.update {
+		rows = append(rows, "update secret\t"+secret.Name)
+	}
+	for _, name := range plan.remove {
+		rows = append(rows, "remove secret\t"+name)
+	}
+	for _, permission := range plan.addPermissions {
+		rows = append(rows, "add permission\t"+permission)
+	}
+	for _, permission := range plan.removePermissions {
+		rows = append(rows, "remove permission\t"+permission)
+	}
+	return rows
+}
+
+func hashSecret(secret client.Secret) ([32]byte, error) {
+	content, err := base64.StdEncoding.DecodeString(secret.Base64Content)
+	if err != nil {
+		return [32]byte{}, errors.Wrapf(err, "Failed to decode secret %s", secret.Name)
+	}
+	return sha256.Sum256(content), nil
+}
+
+// difference returns the values in a that are not in b, sorted
+func difference(a, b []string) []string {
+	inB := make(map[string]bool)
+	for _, value := range b {
+		inB[value] = true
+	}
+	var result []string
+	for _, value := range a {
+		if !inB[value] {
+			result = append(result, value)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/skatteetaten/ao/pkg/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestVault(name string, permissions []string, secrets map[string]string) *client.Vault {
+	vault := client.NewVault(name)
+	vault.Permissions = permissions
+	for _, secretName := range []string{"a.properties", "b.properties", "c.properties", "d.properties"} {
+		if content, ok := secrets[secretName]; ok {
+			vault.AddSecret(client.NewSecret(secretName, base64.StdEncoding.EncodeToString([]byte(content))))
+		}
+	}
+	return vault
+}
+
+func Test_planVaultSync(t *testing.T) {
+	local := newTestVault("foo", []string{"devops", "ops"}, map[string]string{
+		"a.properties": "A=1",
+		"b.properties": "B=2",
+		"c.properties": "C=3",
+	})
+	remote := newTestVault("foo", []string{"devops", "old"}, map[string]string{
+		"a.properties": "A=1",
+		"b.properties": "B=1",
+		"d.properties": "D=4",
+	})
+
+	plan, err := planVaultSync(local, remote, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"add secret\tc.properties",
+		"update secret\tb.properties",
+		"add permission\tops",
+	}, plan.rows("foo"))
+
+	plan, err = planVaultSync(local, remote, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"add secret\tc.properties",
+		"update secret\tb.properties",
+		"remove secret\td.properties",
+		"add permission\tops",
+		"remove permission\told",
+	}, plan.rows("foo"))
+
+	plan, err = planVaultSync(remote, remote, true)
+	assert.NoError(t, err)
+	assert.Empty(t, plan.rows("foo"))
+}
+
+func Test_planVaultSyncWithoutPermissions(t *testing.T) {
+	local := newTestVault("foo", []string{}, map[string]string{"a.properties": "A=1"})
+	remote := newTestVault("foo", []string{"devops"}, map[string]string{"a.properties": "A=1"})
+
+	plan, err := planVaultSync(local, remote, true)
+	assert.NoError(t, err)
+	assert.Empty(t, plan.rows("foo"))
+
+	_, err = planVaultSync(local, nil, false)
+	assert.EqualError(t, err, "Vault foo does not exist, and can not be created without a permissions file")
+
+	local.Permissions = []string{"devops"}
+	plan, err = planVaultSync(local, nil, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"create vault\tfoo", "add secret\ta.properties", "add permission\tdevops"}, plan.rows("foo"))
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/client"
+	"github.com/skatteetaten/ao/pkg/deploymentspec"
+	"github.com/spf13/cobra"
+)
+
+var vaultUsageCmd = &cobra.Command{
+	Use:   "usage <vaultname>",
+	Short: "List the applications referring to a vault in secretVault or secretVaults",
+	RunE:  PrintVaultUsage,
+}
+
+func init() {
+	vaultCmd.AddCommand(vaultUsageCmd)
+}
+
+// PrintVaultUsage is the entry point of the `vault usage` cli command
+func PrintVaultUsage(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return cmd.Usage()
+	}
+
+	rows, err := getVaultUsage(args[0])
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		cmd.Printf("Vault %s is not used by any application\n", args[0])
+		return nil
+	}
+
+	DefaultTablePrinter("APPLICATIONDEPLOYMENTREF\tFIELD", rows, cmd.OutOrStdout())
+	return nil
+}
+
+// warnIfVaultInUse prints a warning listing the applications using a vault, and returns true if there are any
+func warnIfVaultInUse(cmd *cobra.Command, vaultName string) (bool, error) {
+	rows, err := getVaultUsage(vaultName)
+	if err != nil || len(rows) == 0 {
+		return false, err
+	}
+
+	cmd.Printf("WARNING: Vault %s is still used by %d application(s):\n", vaultName, len(rows))
+	DefaultTablePrinter("APPLICATIONDEPLOYMENTREF\tFIELD", rows, cmd.OutOrStdout())
+	return true, nil
+}
+
+// getVaultUsage returns the applications and fields referring to a vault.
+// Applications with invalid deploy specs are included, since they may refer to the vault.
+func getVaultUsage(vaultName string) ([]string, error) {
+	specs, invalid, err := getAllDeploySpecs()
+	if err != nil {
+		return nil, err
+	}
+	rows := findVaultUsage(specs, vaultName)
+	for _, ref := range sortedKeys(invalid) {
+		rows = append(rows, fmt.Sprintf("%s\tinvalid deploy spec: %s", ref, oneLine(invalid[ref])))
+	}
+	return rows, nil
+}
+
+// getAllDeploySpecs gets the deploy specs of all applications, see getDeploySpecs
+func getAllDeploySpecs() ([]deploymentspec.DeploymentSpec, map[string]error, error) {
+	applications, err := getApplications(DefaultAPIClient, "", nil)
+	if err != nil {
+		return nil, nil, err
+	} else if len(applications) == 0 {
+		return nil, nil, nil
+	}
+	return getDeploySpecs(DefaultAPIClient, applications)
+}
+
+// getDeploySpecs gets the deploy specs of applications. If they can not be got together, they are got one by one,
+// and the errors of the applications with invalid deploy specs are returned by ApplicationDeploymentRef.
+// It fails if none of the deploy specs can be got.
+func getDeploySpecs(apiClient client.DeploySpecClient, applications []string) ([]deploymentspec.DeploymentSpec, map[string]error, error) {
+	specs, err := apiClient.GetAuroraDeploySpec(applications, true, false)
+	if err == nil {
+		return specs, nil, nil
+	} else if len(applications) == 1 {
+		return nil, nil, err
+	}
+
+	specs = nil
+	invalid := make(map[string]error)
+	for _, application := range applications {
+		spec, err := apiClient.GetAuroraDeploySpec([]string{application}, true, false)
+		if err != nil {
+			invalid[application] = err
+			continue
+		}
+		specs = append(specs, spec...)
+	}
+	if len(specs) == 0 {
+		return nil, nil, errors.Wrap(err, "Failed to get the deploy specs")
+	}
+	return specs, invalid, nil
+}
+
+// oneLine joins the lines of an error message, so it fits in a table row
+func oneLine(err error) string {
+	return strings.Join(strings.Fields(err.Error()), " ")
+}
+
+func sortedKeys(errs map[string]error) []string {
+	var keys []string
+	for key := range errs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func findVaultUsage(specs []deploymentspec.DeploymentSpec, vaultName string) []string {
+	var rows []string
+	for _, spec := range specs {
+		for _, reference := range spec.VaultReferences() {
+			if reference.Vault == vaultName {
+				rows = append(rows, fmt.Sprintf("%s\t%s", spec.GetString("applicationDeploymentRef"), reference.Field))
+			}
+		}
+	}
+	return rows
+}
+
+// findVaultApplications returns the ApplicationDeploymentRefs of the specs referring to a vault
+func findVaultApplications(specs []deploymentspec.DeploymentSpec, vaultName string) []string {
+	var applications []string
+	for _, spec := range specs {
+		for _, reference := range spec.VaultReferences() {
+			if reference.Vault == vaultName {
+				applications = append(applications, spec.GetString("applicationDeploymentRef"))
+				break
+			}
+		}
+	}
+	return applications
+}
@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/skatteetaten/ao/pkg/deploymentspec"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_findVaultUsage(t *testing.T) {
+	legacy := deploymentspec.NewDeploymentSpec("legacy", "dev", "utv", "1")
+	legacy["secretVault"] = map[string]interface{}{"value": "foo"}
+	app := deploymentspec.NewDeploymentSpec("app", "dev", "utv", "1")
+	app["secretVaults"] = map[string]interface{}{
+		"db":  map[string]interface{}{"name": map[string]interface{}{"value": "foo"}},
+		"foo": map[string]interface{}{"enabled": map[string]interface{}{"value": false}},
+		"bar": map[string]interface{}{},
+	}
+	specs := []deploymentspec.DeploymentSpec{legacy, app}
+
+	assert.Equal(t, []string{"dev/legacy\tsecretVault", "dev/app\tsecretVaults/db"}, findVaultUsage(specs, "foo"))
+	assert.Equal(t, []string{"dev/app\tsecretVaults/bar"}, findVaultUsage(specs, "bar"))
+	assert.Empty(t, findVaultUsage(specs, "baz"))
+
+	assert.Equal(t, []string{"dev/legacy", "dev/app"}, findVaultApplications(specs, "foo"))
+}
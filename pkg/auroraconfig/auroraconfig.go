@@ -23,10 +23,13 @@ type (
 	}
 )
 
-// GetApplicationRefs gets applications reference names
+// GetApplicationRefs gets applications reference names. An empty pattern gets all applications.
 func GetApplicationRefs(filenames FileNames, pattern string, excludes []string) ([]string, error) {
 	possibleDeploys := filenames.GetApplicationDeploymentRefs()
-	applications := SearchForApplications(pattern, possibleDeploys)
+	applications := possibleDeploys
+	if pattern != "" {
+		applications = SearchForApplications(pattern, possibleDeploys)
+	}
 
 	applications, err := filterExcludes(excludes, applications)
 	if err != nil {
@@ -36,6 +39,23 @@ func GetApplicationRefs(filenames FileNames, pattern string, excludes []string)
 	return applications, nil
 }
 
+// Copy returns a copy of the AuroraConfig that is not changed by changes to ac
+func (ac *AuroraConfig) Copy() *AuroraConfig {
+	return &AuroraConfig{
+		Name:  ac.Name,
+		Files: append([]File(nil), ac.Files...),
+	}
+}
+
+// FileNames returns the names of all files in the AuroraConfig
+func (ac *AuroraConfig) FileNames() FileNames {
+	var fileNames FileNames
+	for _, file := range ac.Files {
+		fileNames = append(fileNames, file.Name)
+	}
+	return fileNames
+}
+
 // ToPrettyJSON returns content of file as prettyfied JSON
 func (f *File) ToPrettyJSON() string {
 
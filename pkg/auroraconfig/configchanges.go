@@ -10,7 +10,11 @@ const pathSep = "/"
 
 // RemoveEntry removes a value in an AuroraConfigFile on specified path
 func RemoveEntry(auroraConfigFile *File, path string) error {
-	pathParts := getPathParts(path)
+	return removeEntryAt(auroraConfigFile, getPathParts(path))
+}
+
+// removeEntryAt removes a value in an AuroraConfigFile at the given keys
+func removeEntryAt(auroraConfigFile *File, pathParts []string) error {
 	if len(pathParts) == 0 {
 		return errors.New("path is too short and must contain a named key")
 	}
@@ -28,9 +32,14 @@ func RemoveEntry(auroraConfigFile *File, path string) error {
 	return nil
 }
 
-// SetValue sets a value in an AuroraConfigFile on specified path
-func SetValue(auroraConfigFile *File, path string, value string) error {
-	pathParts := getPathParts(path)
+// SetValue sets a value in an AuroraConfigFile on specified path.
+// The value is either a plain string or a typed value as returned by ParseValue.
+func SetValue(auroraConfigFile *File, path string, value interface{}) error {
+	return setValueAt(auroraConfigFile, getPathParts(path), value)
+}
+
+// setValueAt sets a value in an AuroraConfigFile at the given keys
+func setValueAt(auroraConfigFile *File, pathParts []string, value interface{}) error {
 	if len(pathParts) == 0 {
 		return errors.New("path is too short and must contain a named key")
 	}
@@ -40,10 +40,10 @@ version: 1.2.3
 baseFile: myapp.json
 cluster: utv01
 config:
-  MYAPP_NEW_KEY: newValue
   MYAPP_SOME_KEY: somevalue
   MYAPP_SOME_OTHER_KEY: someothervalue
-replicas: "1"
+  MYAPP_NEW_KEY: newValue
+replicas: '1'
 version: 1.2.3
 `
 		auroraConfigFile := File{
@@ -63,6 +63,124 @@ version: 1.2.3
 
 		assert.Equal(t, expected, changedyaml)
 	})
+	t.Run("Should set typed values in Json AuroraConfigFile", func(t *testing.T) {
+		auroraConfigFile := File{
+			Name:     "myconfigfile.json",
+			Contents: `{"baseFile": "myapp.json"}`,
+		}
+		route, err := ParseValue(`{"enabled":true,"path":"/x"}`, ValueTypeJSON)
+		assert.Nil(t, err)
+		replicas, err := ParseValue("2", ValueTypeInt)
+		assert.Nil(t, err)
+
+		assert.Nil(t, SetValue(&auroraConfigFile, "/route", route))
+		assert.Nil(t, SetValue(&auroraConfigFile, "/replicas", replicas))
+
+		assert.Equal(t, "{\n  \"baseFile\": \"myapp.json\",\n  \"replicas\": 2,\n  \"route\": {\n    \"enabled\": true,\n    \"path\": \"/x\"\n  }\n}\n", auroraConfigFile.Contents)
+	})
+	t.Run("Should set typed values in yaml AuroraConfigFile", func(t *testing.T) {
+		auroraConfigFile := File{
+			Name:     "myconfigfile.yaml",
+			Contents: "---\nbaseFile: myapp.json\n",
+		}
+		route, err := ParseValue("enabled: true\npath: /x", ValueTypeYaml)
+		assert.Nil(t, err)
+		pause, err := ParseValue("true", ValueTypeBool)
+		assert.Nil(t, err)
+
+		assert.Nil(t, SetValue(&auroraConfigFile, "/route", route))
+		assert.Nil(t, SetValue(&auroraConfigFile, "/pause", pause))
+
+		assert.Equal(t, "---\nbaseFile: myapp.json\nroute:\n  enabled: true\n  path: /x\npause: true\n", auroraConfigFile.Contents)
+	})
+	t.Run("Should only change the touched key in yaml AuroraConfigFile", func(t *testing.T) {
+		content := `---
+# Shared settings
+defaults: &defaults
+    cpu: 1
+app:
+    <<: *defaults
+    memory: 128Mi # in megabytes
+config:
+    # Keep this quoted
+    MYAPP_QUOTED: 'quoted'
+    MYAPP_OTHER: other
+version: 1.2.3
+`
+		expected := `---
+# Shared settings
+defaults: &defaults
+    cpu: 1
+app:
+    <<: *defaults
+    memory: 256Mi # in megabytes
+config:
+    # Keep this quoted
+    MYAPP_QUOTED: 'changed'
+version: 1.2.3
+`
+		auroraConfigFile := File{
+			Name:     "myconfigfile.yaml",
+			Contents: content,
+		}
+
+		assert.Nil(t, SetValue(&auroraConfigFile, "/app/memory", "256Mi"))
+		assert.Nil(t, SetValue(&auroraConfigFile, "/config/MYAPP_QUOTED", "changed"))
+		assert.Nil(t, RemoveEntry(&auroraConfigFile, "/config/MYAPP_OTHER"))
+
+		assert.Equal(t, expected, auroraConfigFile.Contents)
+	})
+
+	t.Run("Should keep blank lines and aligned comments in yaml files", func(t *testing.T) {
+		content := `---
+version: 1.2.3   # set by the pipeline
+
+config:
+  MYAPP_A: "a"   # first
+  MYAPP_BB: "b"  # second
+
+route: true
+`
+		expected := `---
+version: 1.2.3   # set by the pipeline
+
+config:
+  MYAPP_A: "a"   # first
+  MYAPP_BB: "changed" # second
+
+route: true
+replicas: 2
+`
+		auroraConfigFile := File{
+			Name:     "myconfigfile.yaml",
+			Contents: content,
+		}
+
+		assert.Nil(t, SetValue(&auroraConfigFile, "/config/MYAPP_BB", "changed"))
+		assert.Nil(t, SetValue(&auroraConfigFile, "/replicas", 2))
+
+		assert.Equal(t, expected, auroraConfigFile.Contents)
+	})
+	t.Run("Should keep the comments of a yaml file without content", func(t *testing.T) {
+		auroraConfigFile := File{
+			Name:     "myconfigfile.yaml",
+			Contents: "---\n# Set the version in each environment\n",
+		}
+
+		assert.Nil(t, SetValue(&auroraConfigFile, "/version", "1.2.3"))
+
+		assert.Equal(t, "---\n# Set the version in each environment\nversion: 1.2.3\n", auroraConfigFile.Contents)
+	})
+	t.Run("Should keep the block style of yaml values", func(t *testing.T) {
+		auroraConfigFile := File{
+			Name:     "myconfigfile.yaml",
+			Contents: "---\nscript: |\n  echo a\n  echo b\nversion: 1.2.3\n",
+		}
+
+		assert.Nil(t, SetValue(&auroraConfigFile, "/script", "echo c\necho d"))
+
+		assert.Equal(t, "---\nscript: |\n  echo c\n  echo d\nversion: 1.2.3\n", auroraConfigFile.Contents)
+	})
 }
 
 func Test_RemoveEntry_Do(t *testing.T) {
@@ -121,7 +239,7 @@ cluster: utv01
 config:
   MYAPP_SOME_KEY: somevalue
   MYAPP_SOME_OTHER_KEY: someothervalue
-replicas: "1"
+replicas: '1'
 version: 1.2.3
 `
 		auroraConfigFile := File{
@@ -200,4 +318,10 @@ func TestGetPartsPath_Do(t *testing.T) {
 		assert.NotNil(t, pathParts)
 		assert.Equal(t, 0, len(pathParts))
 	})
+	t.Run("Should keep ~ in path as it is: /config/a~1b", func(t *testing.T) {
+
+		pathParts := getPathParts("/config/a~1b")
+
+		assert.Equal(t, []string{"config", "a~1b"}, pathParts)
+	})
 }
@@ -0,0 +1,351 @@
+package auroraconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Supported file formats in an AuroraConfig
+const (
+	FormatJSON = "json"
+	FormatYaml = "yaml"
+)
+
+// Keys in AuroraConfig files that refer to other files
+var referenceKeys = []string{"baseFile", "envFile"}
+
+// Reference is a key in an AuroraConfig file that refers to another file
+type Reference struct {
+	File  string
+	Key   string
+	Value string
+}
+
+// Format returns the format of the file, json or yaml
+func (f *File) Format() string {
+	if f.IsYaml() {
+		return FormatYaml
+	}
+	return FormatJSON
+}
+
+// ConvertedName returns the file name with the extension of the given format
+func ConvertedName(fileName string, format string) string {
+	return strings.TrimSuffix(fileName, path.Ext(fileName)) + "." + format
+}
+
+// Convert returns a copy of the file converted to the given format.
+// Key order is kept. Comments are lost when converting from yaml to json.
+func (f *File) Convert(format string) (*File, error) {
+	format = strings.ToLower(format)
+	if format != FormatJSON && format != FormatYaml {
+		return nil, errors.Errorf("unknown format %s, must be %s or %s", format, FormatJSON, FormatYaml)
+	}
+
+	converted := &File{
+		Name:     ConvertedName(f.Name, format),
+		Contents: f.Contents,
+	}
+	if f.Format() == format {
+		return converted, nil
+	}
+
+	if format == FormatYaml {
+		document, err := jsonToYamlDocument(f.Contents)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not parse %s", f.Name)
+		}
+		if err := marshalYamlFile(converted, document); err != nil {
+			return nil, err
+		}
+		return converted, nil
+	}
+
+	document, err := unmarshalYamlFile(f)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not parse %s", f.Name)
+	}
+	content, err := json.MarshalIndent(yamlToJSONValue(yamlRootMapping(document)), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	converted.Contents = string(content) + "\n"
+
+	return converted, nil
+}
+
+// FindReferences finds all references from other files in the AuroraConfig to the given file
+func (ac *AuroraConfig) FindReferences(fileName string) ([]Reference, error) {
+	var references []Reference
+	for i := range ac.Files {
+		file := &ac.Files[i]
+		if file.Name == fileName {
+			continue
+		}
+		values, err := file.referenceValues()
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range referenceKeys {
+			value, ok := values[key]
+			if !ok || resolveReference(file.Name, key, value) != fileName {
+				continue
+			}
+			references = append(references, Reference{File: file.Name, Key: key, Value: value})
+		}
+	}
+	return references, nil
+}
+
+// resolveReference returns the name of the file a reference points to.
+// baseFile refers to a file in the root folder, envFile refers to a file in the same folder.
+func resolveReference(fileName, key, value string) string {
+	if key == "envFile" && strings.Contains(fileName, Separator) {
+		return path.Join(path.Dir(fileName), value)
+	}
+	return value
+}
+
+func (f *File) referenceValues() (map[string]string, error) {
+	var content map[string]interface{}
+	if f.IsYaml() {
+		if err := yaml.Unmarshal([]byte(f.Contents), &content); err != nil {
+			return nil, errors.Wrapf(err, "could not parse %s", f.Name)
+		}
+	} else if err := json.Unmarshal([]byte(f.Contents), &content); err != nil {
+		return nil, errors.Wrapf(err, "could not parse %s", f.Name)
+	}
+
+	values := make(map[string]string)
+	for _, key := range referenceKeys {
+		if value, ok := content[key].(string); ok {
+			values[key] = value
+		}
+	}
+	return values, nil
+}
+
+// GetFile returns the file with the given name, or nil if it does not exist
+func (ac *AuroraConfig) GetFile(fileName string) *File {
+	for i := range ac.Files {
+		if ac.Files[i].Name == fileName {
+			return &ac.Files[i]
+		}
+	}
+	return nil
+}
+
+// ConvertResult describes the changes done by ConvertFiles
+type ConvertResult struct {
+	Renamed    map[string]string
+	References []Reference
+}
+
+// ConvertFiles converts the given files in the AuroraConfig to format.
+// References from other files to a converted file must be updated with updateReferences, or the conversion fails.
+func (ac *AuroraConfig) ConvertFiles(fileNames []string, format string, updateReferences bool) (*ConvertResult, error) {
+	result := &ConvertResult{Renamed: make(map[string]string)}
+
+	for _, fileName := range fileNames {
+		file := ac.GetFile(fileName)
+		if file == nil {
+			return nil, errors.Errorf("could not find %s in AuroraConfig", fileName)
+		}
+		converted, err := file.Convert(format)
+		if err != nil {
+			return nil, err
+		}
+		if converted.Name == file.Name {
+			continue
+		}
+		if ac.GetFile(converted.Name) != nil {
+			return nil, errors.Errorf("can not convert %s, %s already exists", file.Name, converted.Name)
+		}
+
+		references, err := ac.FindReferences(file.Name)
+		if err != nil {
+			return nil, err
+		}
+		if len(references) > 0 && !updateReferences {
+			var referrers []string
+			for _, reference := range references {
+				referrers = append(referrers, reference.File+" ("+reference.Key+")")
+			}
+			return nil, errors.Errorf("%s is referenced from %s, use --update-references to update them", file.Name, strings.Join(referrers, ", "))
+		}
+
+		*file = *converted
+		result.Renamed[fileName] = converted.Name
+
+		for _, reference := range references {
+			referrer := ac.GetFile(reference.File)
+			newValue := strings.TrimSuffix(reference.Value, path.Ext(reference.Value)) + path.Ext(converted.Name)
+			if err := SetValue(referrer, reference.Key, newValue); err != nil {
+				return nil, err
+			}
+			result.References = append(result.References, reference)
+		}
+	}
+
+	return result, nil
+}
+
+// jsonToYamlDocument parses JSON into a yaml document, keeping the order of keys
+func jsonToYamlDocument(content string) (*yaml.Node, error) {
+	decoder := json.NewDecoder(strings.NewReader(content))
+	decoder.UseNumber()
+
+	root, err := jsonToYamlNode(decoder)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.New("unexpected content after end of json document")
+	}
+
+	return &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{root}}, nil
+}
+
+func jsonToYamlNode(decoder *json.Decoder) (*yaml.Node, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch value := token.(type) {
+	case json.Delim:
+		if value == '{' {
+			mapping := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			for decoder.More() {
+				key, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+				valueNode, err := jsonToYamlNode(decoder)
+				if err != nil {
+					return nil, err
+				}
+				mapping.Content = append(mapping.Content, yamlKeyNode(key.(string)), valueNode)
+			}
+			_, err := decoder.Token()
+			return mapping, err
+		}
+		sequence := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for decoder.More() {
+			valueNode, err := jsonToYamlNode(decoder)
+			if err != nil {
+				return nil, err
+			}
+			sequence.Content = append(sequence.Content, valueNode)
+		}
+		_, err := decoder.Token()
+		return sequence, err
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(value.String(), ".eE") {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value.String()}, nil
+	case bool:
+		node := &yaml.Node{}
+		return node, node.Encode(value)
+	case nil:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+	}
+
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: token.(string)}, nil
+}
+
+// jsonObject is a JSON object that keeps the order of its members
+type jsonObject struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (o *jsonObject) set(key string, value interface{}) {
+	if _, exists := o.values[key]; !exists {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+// MarshalJSON writes the members of the object in order
+func (o *jsonObject) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString("{")
+	for i, key := range o.keys {
+		if i > 0 {
+			buffer.WriteString(",")
+		}
+		keyJSON, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		valueJSON, err := json.Marshal(o.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(keyJSON)
+		buffer.WriteString(":")
+		buffer.Write(valueJSON)
+	}
+	buffer.WriteString("}")
+	return buffer.Bytes(), nil
+}
+
+// yamlToJSONValue converts a yaml node to a value that marshals to JSON in the same order.
+// Aliases and merge keys are expanded.
+func yamlToJSONValue(node *yaml.Node) interface{} {
+	switch node.Kind {
+	case yaml.AliasNode:
+		return yamlToJSONValue(node.Alias)
+	case yaml.MappingNode:
+		object := &jsonObject{values: make(map[string]interface{})}
+		var merged []*jsonObject
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Tag == "!!merge" || (key.Value == "<<" && key.Style == 0) {
+				for _, source := range yamlMergeSources(value) {
+					if sourceObject, ok := yamlToJSONValue(source).(*jsonObject); ok {
+						merged = append(merged, sourceObject)
+					}
+				}
+				continue
+			}
+			object.set(key.Value, yamlToJSONValue(value))
+		}
+		for _, source := range merged {
+			for _, key := range source.keys {
+				if _, exists := object.values[key]; !exists {
+					object.set(key, source.values[key])
+				}
+			}
+		}
+		return object
+	case yaml.SequenceNode:
+		list := make([]interface{}, 0, len(node.Content))
+		for _, item := range node.Content {
+			list = append(list, yamlToJSONValue(item))
+		}
+		return list
+	}
+
+	var value interface{}
+	if err := node.Decode(&value); err != nil {
+		return node.Value
+	}
+	return value
+}
+
+func yamlMergeSources(node *yaml.Node) []*yaml.Node {
+	if node.Kind == yaml.SequenceNode {
+		return node.Content
+	}
+	return []*yaml.Node{node}
+}
@@ -0,0 +1,128 @@
+package auroraconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Convert(t *testing.T) {
+	t.Run("Should convert json to yaml keeping key order", func(t *testing.T) {
+		file := File{
+			Name: "test/foo.json",
+			Contents: `{
+	"version": "1.2.3",
+	"replicas": 2,
+	"pause": false,
+	"config": {
+		"B_KEY": "true",
+		"A_KEY": "value"
+	},
+	"hosts": ["a", "b"],
+	"cpu": 0.5
+}`,
+		}
+		expected := `---
+version: 1.2.3
+replicas: 2
+pause: false
+config:
+  B_KEY: "true"
+  A_KEY: value
+hosts:
+  - a
+  - b
+cpu: 0.5
+`
+		converted, err := file.Convert(FormatYaml)
+		assert.NoError(t, err)
+		assert.Equal(t, "test/foo.yaml", converted.Name)
+		assert.Equal(t, expected, converted.Contents)
+	})
+
+	t.Run("Should convert yaml to json keeping key order and expanding anchors", func(t *testing.T) {
+		file := File{
+			Name: "test/foo.yaml",
+			Contents: `---
+# a comment
+version: "1.2.3"
+defaults: &defaults
+  cpu: 1
+resources:
+  <<: *defaults
+  memory: 128Mi
+replicas: 2
+`,
+		}
+		expected := `{
+  "version": "1.2.3",
+  "defaults": {
+    "cpu": 1
+  },
+  "resources": {
+    "memory": "128Mi",
+    "cpu": 1
+  },
+  "replicas": 2
+}
+`
+		converted, err := file.Convert(FormatJSON)
+		assert.NoError(t, err)
+		assert.Equal(t, "test/foo.json", converted.Name)
+		assert.Equal(t, expected, converted.Contents)
+	})
+
+	t.Run("Should fail on unknown format", func(t *testing.T) {
+		file := File{Name: "foo.json", Contents: "{}"}
+		_, err := file.Convert("xml")
+		assert.Error(t, err)
+	})
+}
+
+func Test_ConvertFiles(t *testing.T) {
+	newAuroraConfig := func() *AuroraConfig {
+		return &AuroraConfig{
+			Name: "paas",
+			Files: []File{
+				{Name: "about.json", Contents: `{"schemaVersion": "v1"}`},
+				{Name: "foo.json", Contents: `{"groupId": "no.skatteetaten"}`},
+				{Name: "test/about.json", Contents: `{"cluster": "utv"}`},
+				{Name: "test/about-alt.json", Contents: `{"cluster": "utv04"}`},
+				{Name: "test/foo.yaml", Contents: "---\nbaseFile: foo.json # shared\nenvFile: about-alt.json\n"},
+			},
+		}
+	}
+
+	t.Run("Should fail when converted file is referenced", func(t *testing.T) {
+		ac := newAuroraConfig()
+		_, err := ac.ConvertFiles([]string{"foo.json"}, FormatYaml, false)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "test/foo.yaml (baseFile)")
+		assert.NotNil(t, ac.GetFile("foo.json"))
+	})
+
+	t.Run("Should update references", func(t *testing.T) {
+		ac := newAuroraConfig()
+		result, err := ac.ConvertFiles([]string{"foo.json", "test/about-alt.json"}, FormatYaml, true)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"foo.json": "foo.yaml", "test/about-alt.json": "test/about-alt.yaml"}, result.Renamed)
+		assert.Len(t, result.References, 2)
+		assert.Nil(t, ac.GetFile("foo.json"))
+		assert.Equal(t, "---\ngroupId: no.skatteetaten\n", ac.GetFile("foo.yaml").Contents)
+		assert.Equal(t, "---\nbaseFile: foo.yaml # shared\nenvFile: about-alt.yaml\n", ac.GetFile("test/foo.yaml").Contents)
+	})
+
+	t.Run("Should not convert files already in format", func(t *testing.T) {
+		ac := newAuroraConfig()
+		result, err := ac.ConvertFiles([]string{"test/foo.yaml"}, FormatYaml, false)
+		assert.NoError(t, err)
+		assert.Empty(t, result.Renamed)
+	})
+
+	t.Run("Should fail when converted name exists", func(t *testing.T) {
+		ac := newAuroraConfig()
+		ac.Files = append(ac.Files, File{Name: "test/foo.json", Contents: "{}"})
+		_, err := ac.ConvertFiles([]string{"test/foo.json"}, FormatYaml, false)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,14 @@
+package auroraconfig
+
+import "github.com/pmezard/go-difflib/difflib"
+
+// Diff returns a unified diff between two versions of a file, or an empty string if they are equal
+func Diff(original, modified *File) (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        diffLines(original.Contents),
+		B:        diffLines(modified.Contents),
+		FromFile: "a/" + original.Name,
+		ToFile:   "b/" + modified.Name,
+		Context:  3,
+	})
+}
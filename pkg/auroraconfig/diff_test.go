@@ -0,0 +1,27 @@
+package auroraconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Diff(t *testing.T) {
+	original := &File{Name: "test/foo.yaml", Contents: "---\nversion: \"1.0\"\nreplicas: 2\n"}
+	modified := &File{Name: "test/foo.yaml", Contents: "---\nversion: \"1.1\"\nreplicas: 2\n"}
+
+	diff, err := Diff(original, modified)
+	assert.NoError(t, err)
+	assert.Equal(t, `--- a/test/foo.yaml
++++ b/test/foo.yaml
+@@ -1,3 +1,3 @@
+ ---
+-version: "1.0"
++version: "1.1"
+ replicas: 2
+`, diff)
+
+	diff, err = Diff(original, original)
+	assert.NoError(t, err)
+	assert.Empty(t, diff)
+}
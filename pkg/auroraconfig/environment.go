@@ -0,0 +1,85 @@
+package auroraconfig
+
+import (
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// CloneEnvironment copies all files in the source environment folder to the destination folder
+func (ac *AuroraConfig) CloneEnvironment(source, destination string) ([]*File, error) {
+	if strings.Contains(destination, Separator) || destination == "" {
+		return nil, errors.Errorf("%s is not a valid environment name", destination)
+	}
+	fileNames := ac.FileNames()
+	if existing := fileNames.GetEnvironmentFiles(destination); len(existing) > 0 {
+		return nil, errors.Errorf("environment %s already exists", destination)
+	}
+	sourceFiles := fileNames.GetEnvironmentFiles(source)
+	if len(sourceFiles) == 0 {
+		return nil, errors.Errorf("environment %s does not exist", source)
+	}
+
+	for _, fileName := range sourceFiles {
+		if _, err := ac.CopyFile(fileName, path.Join(destination, path.Base(fileName))); err != nil {
+			return nil, err
+		}
+	}
+
+	var cloned []*File
+	for _, fileName := range sourceFiles {
+		cloned = append(cloned, ac.GetFile(path.Join(destination, path.Base(fileName))))
+	}
+	return cloned, nil
+}
+
+// RewriteValue sets a value in the files that already contain the pointer. If none of them do, the value is
+// set in the about file of the environment, which is the first file named about.
+// It returns the names of the changed files.
+func RewriteValue(files []*File, pointer string, value interface{}) ([]string, error) {
+	var changed []string
+	for _, file := range files {
+		has, err := file.hasValue(pointer)
+		if err != nil {
+			return nil, err
+		}
+		if !has {
+			continue
+		}
+		if err := SetValue(file, pointer, value); err != nil {
+			return nil, err
+		}
+		changed = append(changed, file.Name)
+	}
+	if len(changed) > 0 {
+		return changed, nil
+	}
+
+	for _, file := range files {
+		if strings.TrimSuffix(file.Name, path.Ext(file.Name)) == path.Join(path.Dir(file.Name), aboutFileName) {
+			if err := SetValue(file, pointer, value); err != nil {
+				return nil, err
+			}
+			return []string{file.Name}, nil
+		}
+	}
+	return nil, errors.Errorf("no file contains %s, and there is no about file to set it in", pointer)
+}
+
+func (f *File) hasValue(pointer string) (bool, error) {
+	content, err := f.parseContent()
+	if err != nil {
+		return false, errors.Wrapf(err, "could not parse %s", f.Name)
+	}
+	for _, part := range getPathParts(pointer) {
+		object, ok := content.(map[string]interface{})
+		if !ok {
+			return false, nil
+		}
+		if content, ok = object[part]; !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,56 @@
+package auroraconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CloneEnvironment(t *testing.T) {
+	ac := newFilesTestAuroraConfig()
+
+	cloned, err := ac.CloneEnvironment("utv", "utv05")
+	assert.NoError(t, err)
+	var names []string
+	for _, file := range cloned {
+		names = append(names, file.Name)
+	}
+	assert.Equal(t, []string{"utv05/about-alt.json", "utv05/about.json", "utv05/bar.json", "utv05/foo.json", "utv05/foo2.json"}, names)
+	assert.Equal(t, `{"cluster": "utv"}`, ac.GetFile("utv05/about.json").Contents)
+	assert.NotNil(t, ac.GetFile("utv/about.json"))
+
+	_, err = ac.CloneEnvironment("utv", "utv05")
+	assert.EqualError(t, err, "environment utv05 already exists")
+
+	_, err = ac.CloneEnvironment("test", "test2")
+	assert.EqualError(t, err, "environment test does not exist")
+
+	_, err = ac.CloneEnvironment("utv", "a/b")
+	assert.EqualError(t, err, "a/b is not a valid environment name")
+}
+
+func Test_RewriteValue(t *testing.T) {
+	ac := newFilesTestAuroraConfig()
+	cloned, err := ac.CloneEnvironment("utv", "utv05")
+	assert.NoError(t, err)
+
+	changed, err := RewriteValue(cloned, "/cluster", "utv05")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"utv05/about-alt.json", "utv05/about.json"}, changed)
+	assert.Contains(t, ac.GetFile("utv05/about.json").Contents, `"cluster": "utv05"`)
+
+	changed, err = RewriteValue(cloned, "/replicas", "2")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"utv05/about.json"}, changed)
+
+	changed, err = RewriteValue(cloned, "/version", "3")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"utv05/bar.json", "utv05/foo.json"}, changed)
+}
+
+func Test_RewriteValueWithoutAboutFile(t *testing.T) {
+	files := []*File{{Name: "utv/foo.json", Contents: `{}`}}
+
+	_, err := RewriteValue(files, "/cluster", "utv05")
+	assert.EqualError(t, err, "no file contains /cluster, and there is no about file to set it in")
+}
@@ -1,6 +1,7 @@
 package auroraconfig
 
 import (
+	"path"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -70,3 +71,38 @@ func (f FileNames) Find(name string) (string, error) {
 	}
 	return "", errors.Errorf("could not find %s in AuroraConfig", name)
 }
+
+// Select returns the file names matching a selector. The selector is either a file name with or without
+// extension, an environment folder such as "utv/" or "utv", or a glob pattern such as "utv/*.json"
+func (f FileNames) Select(selector string) []string {
+	if fileName, err := f.Find(selector); err == nil {
+		return []string{fileName}
+	}
+
+	var selected []string
+	folder := strings.TrimSuffix(selector, "/") + "/"
+	for _, fileName := range f {
+		fileNameWithoutExtension := strings.TrimSuffix(fileName, filepath.Ext(fileName))
+		if strings.HasPrefix(fileName, folder) && !strings.Contains(strings.TrimPrefix(fileName, folder), "/") {
+			selected = append(selected, fileName)
+		} else if matched, _ := path.Match(selector, fileName); matched {
+			selected = append(selected, fileName)
+		} else if matched, _ := path.Match(selector, fileNameWithoutExtension); matched {
+			selected = append(selected, fileName)
+		}
+	}
+	sort.Strings(selected)
+	return selected
+}
+
+// GetEnvironmentFiles gets the files in an environment folder
+func (f FileNames) GetEnvironmentFiles(environment string) []string {
+	var files []string
+	for _, file := range f {
+		if strings.HasPrefix(file, environment+"/") {
+			files = append(files, file)
+		}
+	}
+	sort.Strings(files)
+	return files
+}
@@ -0,0 +1,35 @@
+package auroraconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileNames_Select(t *testing.T) {
+	fileNames := FileNames{"about.json", "foo.json", "test/about.json", "test/foo.yaml", "test/bar.json", "prod/foo.json"}
+
+	cases := []struct {
+		Selector string
+		Expected []string
+	}{
+		{"test/foo", []string{"test/foo.yaml"}},
+		{"foo.json", []string{"foo.json"}},
+		{"test", []string{"test/about.json", "test/bar.json", "test/foo.yaml"}},
+		{"test/", []string{"test/about.json", "test/bar.json", "test/foo.yaml"}},
+		{"*/foo", []string{"prod/foo.json", "test/foo.yaml"}},
+		{"test/*.json", []string{"test/about.json", "test/bar.json"}},
+		{"utv", nil},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.Expected, fileNames.Select(tc.Selector), tc.Selector)
+	}
+}
+
+func TestFileNames_GetEnvironmentFiles(t *testing.T) {
+	fileNames := FileNames{"utv.json", "utv/foo.json", "utv/about.json", "utv05/foo.json"}
+
+	assert.Equal(t, []string{"utv/about.json", "utv/foo.json"}, fileNames.GetEnvironmentFiles("utv"))
+	assert.Empty(t, fileNames.GetEnvironmentFiles("test"))
+}
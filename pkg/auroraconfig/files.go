@@ -0,0 +1,194 @@
+package auroraconfig
+
+import (
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// FileImpact describes what depends on a file in an AuroraConfig
+type FileImpact struct {
+	// References are baseFile and envFile keys in other files referring to the file
+	References []Reference
+	// ImplicitReferences are the baseFile, envFile and globalFile keys left out of other files that use the file by default
+	ImplicitReferences []Reference
+	// ApplicationDeploymentRefs are the application deployments defined by the file itself
+	ApplicationDeploymentRefs []string
+	// UsedBy are the other application deployments whose configuration includes the file
+	UsedBy []string
+}
+
+// FileImpact finds the references and application deployments that depend on a file
+func (ac *AuroraConfig) FileImpact(fileName string) (*FileImpact, error) {
+	if ac.GetFile(fileName) == nil {
+		return nil, errors.Errorf("could not find %s in AuroraConfig", fileName)
+	}
+
+	references, err := ac.FindReferences(fileName)
+	if err != nil {
+		return nil, err
+	}
+	impact := &FileImpact{References: references}
+
+	ownRef := strings.TrimSuffix(fileName, path.Ext(fileName))
+	for _, ref := range ac.FileNames().GetApplicationDeploymentRefs() {
+		if ref == ownRef {
+			impact.ApplicationDeploymentRefs = append(impact.ApplicationDeploymentRefs, ref)
+			continue
+		}
+		files, err := ac.SpecFiles(ref)
+		if err != nil {
+			logrus.Debugf("Skipping %s when looking for usages of %s: %v", ref, fileName, err)
+			continue
+		}
+		for _, file := range files {
+			if file.Name == fileName {
+				impact.UsedBy = append(impact.UsedBy, ref)
+				break
+			}
+		}
+		defaults, err := ac.defaultReferences(ref)
+		if err != nil {
+			logrus.Debugf("Skipping %s when looking for usages of %s: %v", ref, fileName, err)
+			continue
+		}
+		for _, reference := range defaults {
+			if reference.Value == fileName && !containsReference(impact.ImplicitReferences, reference) {
+				impact.ImplicitReferences = append(impact.ImplicitReferences, reference)
+			}
+		}
+	}
+	return impact, nil
+}
+
+// BreakingReferences returns the references and implicit references that stop working if the file is removed
+func (impact *FileImpact) BreakingReferences() []Reference {
+	return append(append([]Reference{}, impact.References...), impact.ImplicitReferences...)
+}
+
+func containsReference(references []Reference, reference Reference) bool {
+	for _, r := range references {
+		if r == reference {
+			return true
+		}
+	}
+	return false
+}
+
+// RemoveFile removes a file from the AuroraConfig
+func (ac *AuroraConfig) RemoveFile(fileName string) error {
+	for i := range ac.Files {
+		if ac.Files[i].Name == fileName {
+			ac.Files = append(ac.Files[:i], ac.Files[i+1:]...)
+			return nil
+		}
+	}
+	return errors.Errorf("could not find %s in AuroraConfig", fileName)
+}
+
+// CopyFile adds a copy of a file to the AuroraConfig. If destination has no extension, the extension of
+// the source is used. If it has another extension, the copy is converted to that format.
+func (ac *AuroraConfig) CopyFile(source, destination string) (*File, error) {
+	copied, err := ac.newFileFrom(source, destination)
+	if err != nil {
+		return nil, err
+	}
+	ac.Files = append(ac.Files, *copied)
+	return copied, nil
+}
+
+// MoveFile renames a file in the AuroraConfig, converting it if the extension changes.
+// References from other files to the file must be updated with updateReferences, or the move fails. This includes
+// files using the file by default without a baseFile, envFile or globalFile key, which get the key added.
+func (ac *AuroraConfig) MoveFile(source, destination string, updateReferences bool) (*File, []Reference, error) {
+	moved, err := ac.newFileFrom(source, destination)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	impact, err := ac.FileImpact(source)
+	if err != nil {
+		return nil, nil, err
+	}
+	references := impact.References
+	// Files are used by default regardless of their extension, so only a new name breaks the implicit references
+	if trimFileExtension(moved.Name) != trimFileExtension(source) {
+		references = impact.BreakingReferences()
+	}
+	if len(references) > 0 && !updateReferences {
+		var referrers []string
+		for _, reference := range references {
+			referrers = append(referrers, reference.File+" ("+reference.Key+")")
+		}
+		return nil, nil, errors.Errorf("%s is referenced from %s, use --update-references to update them", source, strings.Join(referrers, ", "))
+	}
+
+	newValues := make([]string, len(references))
+	for i, reference := range references {
+		newValues[i], err = referenceValue(reference.File, reference.Key, moved.Name)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	*ac.GetFile(source) = *moved
+	for i, reference := range references {
+		if err := SetValue(ac.GetFile(reference.File), reference.Key, newValues[i]); err != nil {
+			return nil, nil, err
+		}
+	}
+	return moved, references, nil
+}
+
+func (ac *AuroraConfig) newFileFrom(source, destination string) (*File, error) {
+	file := ac.GetFile(source)
+	if file == nil {
+		return nil, errors.Errorf("could not find %s in AuroraConfig", source)
+	}
+
+	format := file.Format()
+	switch strings.ToLower(strings.TrimPrefix(path.Ext(destination), ".")) {
+	case "":
+		destination += path.Ext(source)
+	case FormatJSON:
+		format = FormatJSON
+	case FormatYaml, "yml":
+		format = FormatYaml
+	default:
+		return nil, errors.Errorf("%s must have a %s or %s extension", destination, FormatJSON, FormatYaml)
+	}
+	if strings.Count(destination, Separator) > 1 {
+		return nil, errors.Errorf("%s is nested too deep, files must be in the root or in an environment folder", destination)
+	}
+	existing, err := ac.findFileIgnoringExtension(destination)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.Name != source {
+		return nil, errors.Errorf("%s already exists", existing.Name)
+	}
+
+	converted, err := file.Convert(format)
+	if err != nil {
+		return nil, err
+	}
+	converted.Name = destination
+	return converted, nil
+}
+
+// referenceValue returns the value a reference must have to refer to target.
+// baseFile refers to a file in the root folder, envFile refers to a file in the same folder.
+func referenceValue(referrer, key, target string) (string, error) {
+	if key == "envFile" && strings.Contains(referrer, Separator) {
+		if path.Dir(target) != path.Dir(referrer) {
+			return "", errors.Errorf("%s in %s can not refer to %s, it must be in the same folder", key, referrer, target)
+		}
+		return path.Base(target), nil
+	}
+	if strings.Contains(target, Separator) {
+		return "", errors.Errorf("%s in %s can not refer to %s, it must be in the root folder", key, referrer, target)
+	}
+	return target, nil
+}
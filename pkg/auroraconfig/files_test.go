@@ -0,0 +1,125 @@
+package auroraconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newFilesTestAuroraConfig() *AuroraConfig {
+	return &AuroraConfig{
+		Name: "paas",
+		Files: []File{
+			{Name: "about.json", Contents: `{"affiliation": "paas"}`},
+			{Name: "foo.json", Contents: `{"type": "deploy"}`},
+			{Name: "bar.json", Contents: `{"type": "deploy"}`},
+			{Name: "utv/about.json", Contents: `{"cluster": "utv"}`},
+			{Name: "utv/about-alt.json", Contents: `{"cluster": "utv02"}`},
+			{Name: "utv/foo.json", Contents: `{"version": "1"}`},
+			{Name: "utv/foo2.json", Contents: `{"baseFile": "foo.json", "envFile": "about-alt.json"}`},
+			{Name: "utv/bar.json", Contents: `{"version": "2"}`},
+		},
+	}
+}
+
+func Test_FileImpact(t *testing.T) {
+	ac := newFilesTestAuroraConfig()
+
+	impact, err := ac.FileImpact("foo.json")
+	assert.NoError(t, err)
+	assert.Equal(t, []Reference{{File: "utv/foo2.json", Key: "baseFile", Value: "foo.json"}}, impact.References)
+	assert.Equal(t, []Reference{{File: "utv/foo.json", Key: "baseFile", Value: "foo.json"}}, impact.ImplicitReferences)
+	assert.Empty(t, impact.ApplicationDeploymentRefs)
+	assert.Equal(t, []string{"utv/foo", "utv/foo2"}, impact.UsedBy)
+
+	impact, err = ac.FileImpact("utv/bar.json")
+	assert.NoError(t, err)
+	assert.Empty(t, impact.References)
+	assert.Equal(t, []string{"utv/bar"}, impact.ApplicationDeploymentRefs)
+	assert.Empty(t, impact.UsedBy)
+
+	impact, err = ac.FileImpact("utv/about.json")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"utv/bar", "utv/foo"}, impact.UsedBy)
+	assert.Equal(t, []Reference{
+		{File: "utv/bar.json", Key: "envFile", Value: "utv/about.json"},
+		{File: "utv/foo.json", Key: "envFile", Value: "utv/about.json"},
+	}, impact.ImplicitReferences)
+
+	impact, err = ac.FileImpact("about.json")
+	assert.NoError(t, err)
+	assert.Equal(t, []Reference{
+		{File: "utv/about.json", Key: "globalFile", Value: "about.json"},
+		{File: "utv/about-alt.json", Key: "globalFile", Value: "about.json"},
+	}, impact.ImplicitReferences)
+
+	_, err = ac.FileImpact("missing.json")
+	assert.EqualError(t, err, "could not find missing.json in AuroraConfig")
+}
+
+func Test_RemoveFile(t *testing.T) {
+	ac := newFilesTestAuroraConfig()
+
+	assert.NoError(t, ac.RemoveFile("utv/bar.json"))
+	assert.Nil(t, ac.GetFile("utv/bar.json"))
+	assert.Len(t, ac.Files, 7)
+	assert.Error(t, ac.RemoveFile("utv/bar.json"))
+}
+
+func Test_CopyFile(t *testing.T) {
+	ac := newFilesTestAuroraConfig()
+
+	copied, err := ac.CopyFile("utv/bar.json", "test/bar")
+	assert.NoError(t, err)
+	assert.Equal(t, File{Name: "test/bar.json", Contents: `{"version": "2"}`}, *copied)
+	assert.NotNil(t, ac.GetFile("utv/bar.json"))
+
+	copied, err = ac.CopyFile("utv/bar.json", "test/baz.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, "---\nversion: \"2\"\n", copied.Contents)
+
+	_, err = ac.CopyFile("utv/bar.json", "utv/foo.yaml")
+	assert.EqualError(t, err, "utv/foo.json already exists")
+
+	_, err = ac.CopyFile("utv/bar.json", "utv/bar/baz.json")
+	assert.EqualError(t, err, "utv/bar/baz.json is nested too deep, files must be in the root or in an environment folder")
+
+	_, err = ac.CopyFile("utv/bar.json", "utv/bar.txt")
+	assert.EqualError(t, err, "utv/bar.txt must have a json or yaml extension")
+}
+
+func Test_MoveFile(t *testing.T) {
+	ac := newFilesTestAuroraConfig()
+
+	_, _, err := ac.MoveFile("foo.json", "foobar.json", false)
+	assert.EqualError(t, err, "foo.json is referenced from utv/foo2.json (baseFile), utv/foo.json (baseFile), use --update-references to update them")
+
+	moved, references, err := ac.MoveFile("foo.json", "foobar.json", true)
+	assert.NoError(t, err)
+	assert.Equal(t, "foobar.json", moved.Name)
+	assert.Len(t, references, 2)
+	assert.Nil(t, ac.GetFile("foo.json"))
+	assert.Equal(t, "{\n  \"baseFile\": \"foobar.json\",\n  \"envFile\": \"about-alt.json\"\n}\n", ac.GetFile("utv/foo2.json").Contents)
+	assert.Equal(t, "{\n  \"baseFile\": \"foobar.json\",\n  \"version\": \"1\"\n}\n", ac.GetFile("utv/foo.json").Contents)
+
+	_, _, err = ac.MoveFile("utv/about-alt.json", "test/about-alt.json", true)
+	assert.EqualError(t, err, "envFile in utv/foo2.json can not refer to test/about-alt.json, it must be in the same folder")
+	assert.NotNil(t, ac.GetFile("utv/about-alt.json"))
+
+	moved, _, err = ac.MoveFile("utv/bar.json", "test/bar.yaml", false)
+	assert.NoError(t, err)
+	assert.Equal(t, File{Name: "test/bar.yaml", Contents: "---\nversion: \"2\"\n"}, *moved)
+	assert.Nil(t, ac.GetFile("utv/bar.json"))
+}
+
+func Test_MoveFileUsedByDefault(t *testing.T) {
+	ac := newFilesTestAuroraConfig()
+
+	_, _, err := ac.MoveFile("bar.json", "baz.json", false)
+	assert.EqualError(t, err, "bar.json is referenced from utv/bar.json (baseFile), use --update-references to update them")
+
+	moved, references, err := ac.MoveFile("bar.json", "bar.yaml", false)
+	assert.NoError(t, err)
+	assert.Equal(t, "bar.yaml", moved.Name)
+	assert.Empty(t, references)
+}
@@ -0,0 +1,136 @@
+package auroraconfig
+
+import (
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// GroupPrefix marks a search as the name of a group of applications, as in @payments
+const GroupPrefix = "@"
+
+// GroupFile is the name, without extension, of the file in the root of an AuroraConfig defining groups.
+// It starts with "about", so it is not mistaken for an application.
+const GroupFile = "about-groups"
+
+// Group is a named set of applications that are usually handled together
+type Group struct {
+	Name string
+	// Members are ApplicationDeploymentRefs, or other groups prefixed with GroupPrefix
+	Members []string
+	// Source is where the group is defined
+	Source string
+}
+
+// Groups holds groups by name
+type Groups map[string]Group
+
+// IsGroup returns true if search refers to a group
+func IsGroup(search string) bool {
+	return strings.HasPrefix(search, GroupPrefix)
+}
+
+// FindGroupFile returns the name of the group file, or an empty string if there is none
+func (f FileNames) FindGroupFile() string {
+	for _, fileName := range f {
+		if strings.TrimSuffix(fileName, path.Ext(fileName)) == GroupFile {
+			return fileName
+		}
+	}
+	return ""
+}
+
+// ParseGroupFile parses a group file. Each key is the name of a group, and the value is either
+// a list of members, or a string of members separated by whitespace.
+func ParseGroupFile(file *File) (map[string][]string, error) {
+	content, err := file.parseContent()
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not parse %s", file.Name)
+	}
+	object, ok := content.(map[string]interface{})
+	if !ok {
+		return nil, errors.Errorf("%s must be an object of groups", file.Name)
+	}
+
+	groups := make(map[string][]string)
+	for name, value := range object {
+		switch members := value.(type) {
+		case string:
+			groups[name] = strings.Fields(members)
+		case []interface{}:
+			for _, member := range members {
+				text, ok := member.(string)
+				if !ok {
+					return nil, errors.Errorf("group %s in %s must only have text members", name, file.Name)
+				}
+				groups[name] = append(groups[name], text)
+			}
+		default:
+			return nil, errors.Errorf("group %s in %s must be a list of members", name, file.Name)
+		}
+	}
+	return groups, nil
+}
+
+// Add adds groups defined in source, replacing groups with the same name
+func (g Groups) Add(source string, groups map[string][]string) {
+	for name, members := range groups {
+		name = strings.TrimPrefix(name, GroupPrefix)
+		g[name] = Group{Name: name, Members: members, Source: source}
+	}
+}
+
+// Expand returns the ApplicationDeploymentRefs in a group, including those of the groups in it
+func (g Groups) Expand(name string, fileNames FileNames) ([]string, error) {
+	applications := make(map[string]bool)
+	for _, application := range fileNames.GetApplicationDeploymentRefs() {
+		applications[application] = true
+	}
+
+	var expanded []string
+	seen := make(map[string]bool)
+	if err := g.expand(strings.TrimPrefix(name, GroupPrefix), applications, nil, seen, &expanded); err != nil {
+		return nil, err
+	}
+	return expanded, nil
+}
+
+func (g Groups) expand(name string, applications map[string]bool, parents []string, seen map[string]bool, expanded *[]string) error {
+	for _, parent := range parents {
+		if parent == name {
+			return errors.Errorf("group %s%s includes itself", GroupPrefix, name)
+		}
+	}
+	group, ok := g[name]
+	if !ok {
+		return errors.Errorf("no group named %s%s", GroupPrefix, name)
+	}
+
+	for _, member := range group.Members {
+		if IsGroup(member) {
+			if err := g.expand(strings.TrimPrefix(member, GroupPrefix), applications, append(parents, name), seen, expanded); err != nil {
+				return err
+			}
+			continue
+		}
+		member = strings.TrimSuffix(member, path.Ext(member))
+		if !applications[member] {
+			return errors.Errorf("group %s%s has the member %s, which is not an application", GroupPrefix, name, member)
+		}
+		if !seen[member] {
+			seen[member] = true
+			*expanded = append(*expanded, member)
+		}
+	}
+	return nil
+}
+
+// GetGroupApplicationRefs gets the ApplicationDeploymentRefs in a group, except those matching excludes
+func GetGroupApplicationRefs(groups Groups, fileNames FileNames, name string, excludes []string) ([]string, error) {
+	applications, err := groups.Expand(name, fileNames)
+	if err != nil {
+		return nil, err
+	}
+	return filterExcludes(excludes, applications)
+}
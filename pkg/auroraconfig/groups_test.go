@@ -0,0 +1,52 @@
+package auroraconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGroupFile(t *testing.T) {
+	file := &File{Name: "about-groups.yaml", Contents: "payments: [prod/pay-api, prod/pay-worker]\nall: \"@payments prod/crm\"\n"}
+	groups, err := ParseGroupFile(file)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"payments": {"prod/pay-api", "prod/pay-worker"},
+		"all":      {"@payments", "prod/crm"},
+	}, groups)
+
+	_, err = ParseGroupFile(&File{Name: "about-groups.json", Contents: `{"payments": 1}`})
+	assert.EqualError(t, err, "group payments in about-groups.json must be a list of members")
+}
+
+func TestGroupsExpand(t *testing.T) {
+	fileNames := FileNames{"about.json", "about-groups.json", "prod/about.json", "prod/pay-api.json", "prod/pay-worker.yaml", "prod/crm.json"}
+	assert.Equal(t, "about-groups.json", fileNames.FindGroupFile())
+
+	groups := Groups{}
+	groups.Add("about-groups.json", map[string][]string{
+		"payments": {"prod/pay-api", "prod/pay-worker.yaml"},
+		"all":      {"@payments", "prod/crm", "prod/pay-api"},
+		"loop":     {"@loop"},
+		"missing":  {"prod/foo"},
+	})
+	groups.Add(".ao-config.json", map[string][]string{"@crm": {"prod/crm"}})
+	assert.Equal(t, ".ao-config.json", groups["crm"].Source)
+
+	expanded, err := groups.Expand("@all", fileNames)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"prod/pay-api", "prod/pay-worker", "prod/crm"}, expanded)
+
+	_, err = groups.Expand("@loop", fileNames)
+	assert.EqualError(t, err, "group @loop includes itself")
+
+	_, err = groups.Expand("@missing", fileNames)
+	assert.EqualError(t, err, "group @missing has the member prod/foo, which is not an application")
+
+	_, err = groups.Expand("@unknown", fileNames)
+	assert.EqualError(t, err, "no group named @unknown")
+
+	applications, err := GetGroupApplicationRefs(groups, fileNames, "@all", []string{".*/crm"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"prod/pay-api", "prod/pay-worker"}, applications)
+}
@@ -52,7 +52,7 @@ func jsonRemoveEntryRecursive(jsonContent *map[string]interface{}, pathParts []s
 }
 
 // SetValue sets a value in an AuroraConfigFile on specified path
-func jsonSetValue(auroraConfigFile *File, pathParts []string, value string) error {
+func jsonSetValue(auroraConfigFile *File, pathParts []string, value interface{}) error {
 
 	var content map[string]interface{}
 	// Unmarshal content from file
@@ -73,7 +73,7 @@ func jsonSetValue(auroraConfigFile *File, pathParts []string, value string) erro
 	return nil
 }
 
-func jsonSetOrCreateRecursive(content *map[string]interface{}, pathParts []string, value string) error {
+func jsonSetOrCreateRecursive(content *map[string]interface{}, pathParts []string, value interface{}) error {
 	firstOfPath, err := validateAndGetFirstOfPath(pathParts)
 	if err != nil {
 		return err
@@ -97,8 +97,8 @@ func jsonSetOrCreateRecursive(content *map[string]interface{}, pathParts []strin
 	return nil
 }
 
-func jsonSetFoundValue(content *map[string]interface{}, key string, value string) error {
-	logrus.Debugf("Setting %s = %s\n", key, value)
+func jsonSetFoundValue(content *map[string]interface{}, key string, value interface{}) error {
+	logrus.Debugf("Setting %s = %v\n", key, value)
 	(*content)[key] = value
 	return nil
 }
@@ -0,0 +1,179 @@
+package auroraconfig
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Conflict markers used by MergeWithMarkers
+const (
+	MarkerMine   = "<<<<<<< mine"
+	MarkerBase   = "======="
+	MarkerTheirs = ">>>>>>> theirs"
+)
+
+// MergeConflict is a value changed differently in two versions of a file
+type MergeConflict struct {
+	Path   string
+	Mine   interface{}
+	Theirs interface{}
+}
+
+type mergeChange struct {
+	// segments are the keys of the value, used as they are since keys may contain slashes
+	segments []string
+	value    interface{}
+	remove   bool
+}
+
+// missingKey is the value of a key that is not in an object, so that it differs from a key set to null
+type missingKey struct{}
+
+// Merge applies the changes made from base to theirs to mine, keeping the formatting of mine.
+// Values changed differently in mine and theirs are conflicts, and nothing is merged if there are any.
+func Merge(base, mine, theirs *File) (*File, []MergeConflict, error) {
+	var contents [3]interface{}
+	for i, file := range []*File{base, mine, theirs} {
+		content, err := file.parseContent()
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "could not merge %s", file.Name)
+		}
+		contents[i] = content
+	}
+	if contents[0] == nil {
+		contents[0] = missingKey{}
+	}
+
+	var changes []mergeChange
+	var conflicts []MergeConflict
+	mergeValues(nil, contents[0], contents[1], contents[2], &changes, &conflicts)
+	if len(conflicts) > 0 {
+		return nil, conflicts, nil
+	}
+
+	merged := *mine
+	for _, change := range changes {
+		var err error
+		if change.remove {
+			err = removeEntryAt(&merged, change.segments)
+		} else {
+			err = setValueAt(&merged, change.segments, change.value)
+		}
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "could not merge %s into %s", jsonPointer(change.segments), mine.Name)
+		}
+	}
+	return &merged, nil, nil
+}
+
+// mergeValues collects the changes to apply to mine, and the conflicts, for the value at segments.
+// A missingKey value means the key is missing, while nil means it is set to null.
+func mergeValues(segments []string, base, mine, theirs interface{}, changes *[]mergeChange, conflicts *[]MergeConflict) {
+	switch {
+	case reflect.DeepEqual(mine, theirs), reflect.DeepEqual(base, theirs):
+		return
+	case reflect.DeepEqual(base, mine):
+		if len(segments) == 0 {
+			break
+		}
+		_, remove := theirs.(missingKey)
+		*changes = append(*changes, mergeChange{segments: segments, value: theirs, remove: remove})
+		return
+	}
+
+	mineObject, mineIsObject := mine.(map[string]interface{})
+	theirsObject, theirsIsObject := theirs.(map[string]interface{})
+	baseObject, baseIsObject := base.(map[string]interface{})
+	if _, missing := base.(missingKey); missing {
+		baseObject, baseIsObject = map[string]interface{}{}, true
+	}
+	if !mineIsObject || !theirsIsObject || !baseIsObject {
+		*conflicts = append(*conflicts, MergeConflict{Path: jsonPointer(segments), Mine: withoutMissing(mine), Theirs: withoutMissing(theirs)})
+		return
+	}
+
+	keys := make(map[string]bool)
+	for _, object := range []map[string]interface{}{baseObject, mineObject, theirsObject} {
+		for key := range object {
+			keys[key] = true
+		}
+	}
+	var sortedKeys []string
+	for key := range keys {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+
+	for _, key := range sortedKeys {
+		mergeValues(appendSegment(segments, key), getKey(baseObject, key), getKey(mineObject, key), getKey(theirsObject, key), changes, conflicts)
+	}
+}
+
+func getKey(object map[string]interface{}, key string) interface{} {
+	if value, exists := object[key]; exists {
+		return value
+	}
+	return missingKey{}
+}
+
+// withoutMissing returns nil for a missing key, for use in conflicts
+func withoutMissing(value interface{}) interface{} {
+	if _, missing := value.(missingKey); missing {
+		return nil
+	}
+	return value
+}
+
+// MergeWithMarkers merges the lines of base, mine and theirs. Lines changed differently in mine and
+// theirs are surrounded by conflict markers. It returns the merged contents and the number of conflicts.
+func MergeWithMarkers(base, mine, theirs string) (string, int) {
+	baseLines, mineLines, theirsLines := diffLines(base), diffLines(mine), diffLines(theirs)
+	mineMatches := matchLines(baseLines, mineLines)
+	theirsMatches := matchLines(baseLines, theirsLines)
+
+	var merged []string
+	conflicts := 0
+	b, m, t := 0, 0, 0
+	for {
+		// Find the next base line that is unchanged in both mine and theirs
+		next := b
+		for next < len(baseLines) && (mineMatches[next] < 0 || theirsMatches[next] < 0) {
+			next++
+		}
+		mineEnd, theirsEnd := len(mineLines), len(theirsLines)
+		if next < len(baseLines) {
+			mineEnd, theirsEnd = mineMatches[next], theirsMatches[next]
+		}
+
+		lines, conflict := mergeChunk(baseLines[b:next], mineLines[m:mineEnd], theirsLines[t:theirsEnd])
+		merged = append(merged, lines...)
+		if conflict {
+			conflicts++
+		}
+
+		if next == len(baseLines) {
+			break
+		}
+		merged = append(merged, baseLines[next])
+		b, m, t = next+1, mineEnd+1, theirsEnd+1
+	}
+	return strings.Join(merged, ""), conflicts
+}
+
+func mergeChunk(base, mine, theirs []string) ([]string, bool) {
+	switch {
+	case reflect.DeepEqual(mine, theirs), reflect.DeepEqual(base, theirs):
+		return mine, false
+	case reflect.DeepEqual(base, mine):
+		return theirs, false
+	}
+
+	lines := []string{MarkerMine + "\n"}
+	lines = append(lines, mine...)
+	lines = append(lines, MarkerBase+"\n")
+	lines = append(lines, theirs...)
+	return append(lines, MarkerTheirs+"\n"), true
+}
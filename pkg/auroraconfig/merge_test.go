@@ -0,0 +1,109 @@
+package auroraconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge(t *testing.T) {
+	base := &File{Name: "utv/foo.json", Contents: `{
+  "version": "1",
+  "config": {
+    "A": "a",
+    "B": "b"
+  }
+}`}
+	mine := &File{Name: "utv/foo.json", Contents: `{
+  "version": "2",
+  "config": {
+    "A": "a",
+    "B": "b"
+  }
+}`}
+	theirs := &File{Name: "utv/foo.json", Contents: `{
+  "version": "1",
+  "config": {
+    "A": "a",
+    "C": "c"
+  },
+  "replicas": 2
+}`}
+
+	merged, conflicts, err := Merge(base, mine, theirs)
+	assert.NoError(t, err)
+	assert.Empty(t, conflicts)
+	assert.JSONEq(t, `{"version": "2", "config": {"A": "a", "C": "c"}, "replicas": 2}`, merged.Contents)
+	assert.Equal(t, "utv/foo.json", merged.Name)
+}
+
+func TestMergeYamlKeepsComments(t *testing.T) {
+	base := &File{Name: "foo.yaml", Contents: "---\n# The version\nversion: \"1\"\nreplicas: 1\n"}
+	mine := &File{Name: "foo.yaml", Contents: "---\n# The version\nversion: \"2\"\nreplicas: 1\n"}
+	theirs := &File{Name: "foo.yaml", Contents: "---\nversion: \"1\"\nreplicas: 3\n"}
+
+	merged, conflicts, err := Merge(base, mine, theirs)
+	assert.NoError(t, err)
+	assert.Empty(t, conflicts)
+	assert.Equal(t, "---\n# The version\nversion: \"2\"\nreplicas: 3\n", merged.Contents)
+}
+
+func TestMergeNullAndKeysWithSlash(t *testing.T) {
+	base := &File{Name: "foo.json", Contents: `{"version": "1", "config": {"a/b": "1", "c~d": "1"}, "pause": false}`}
+	mine := &File{Name: "foo.json", Contents: `{"version": "2", "config": {"a/b": "1", "c~d": "1"}, "pause": false}`}
+	theirs := &File{Name: "foo.json", Contents: `{"version": "1", "config": {"a/b": "2", "c~d": "2"}, "pause": null}`}
+
+	merged, conflicts, err := Merge(base, mine, theirs)
+	assert.NoError(t, err)
+	assert.Empty(t, conflicts)
+	assert.JSONEq(t, `{"version": "2", "config": {"a/b": "2", "c~d": "2"}, "pause": null}`, merged.Contents)
+
+	yamlBase := &File{Name: "foo.yaml", Contents: "---\nversion: \"1\"\npause: false\n"}
+	yamlMine := &File{Name: "foo.yaml", Contents: "---\nversion: \"2\"\npause: false\n"}
+	yamlTheirs := &File{Name: "foo.yaml", Contents: "---\nversion: \"1\"\npause: null\n"}
+
+	merged, conflicts, err = Merge(yamlBase, yamlMine, yamlTheirs)
+	assert.NoError(t, err)
+	assert.Empty(t, conflicts)
+	assert.Equal(t, "---\nversion: \"2\"\npause: null\n", merged.Contents)
+}
+
+func TestMergeWithConflicts(t *testing.T) {
+	base := &File{Name: "foo.json", Contents: `{"version": "1", "config": {"A": "a"}}`}
+	mine := &File{Name: "foo.json", Contents: `{"version": "2", "config": {"A": "a", "B": "b"}}`}
+	theirs := &File{Name: "foo.json", Contents: `{"version": "3"}`}
+
+	merged, conflicts, err := Merge(base, mine, theirs)
+	assert.NoError(t, err)
+	assert.Nil(t, merged)
+	assert.Equal(t, []MergeConflict{
+		{Path: "/config", Mine: map[string]interface{}{"A": "a", "B": "b"}, Theirs: nil},
+		{Path: "/version", Mine: "2", Theirs: "3"},
+	}, conflicts)
+
+	_, _, err = Merge(base, &File{Name: "foo.json", Contents: `{`}, theirs)
+	assert.Error(t, err)
+}
+
+func TestMergeWithMarkers(t *testing.T) {
+	base := "{\n  \"version\": \"1\",\n  \"replicas\": 1,\n  \"route\": true\n}"
+	mine := "{\n  \"version\": \"2\",\n  \"replicas\": 1,\n  \"route\": true\n}"
+	theirs := "{\n  \"version\": \"3\",\n  \"replicas\": 1,\n  \"route\": false\n}"
+
+	merged, conflicts := MergeWithMarkers(base, mine, theirs)
+	assert.Equal(t, 1, conflicts)
+	assert.Equal(t, `{
+<<<<<<< mine
+  "version": "2",
+=======
+  "version": "3",
+>>>>>>> theirs
+  "replicas": 1,
+  "route": false
+}
+`, merged)
+
+	merged, conflicts = MergeWithMarkers(base, mine, base)
+	assert.Equal(t, 0, conflicts)
+	assert.Equal(t, mine+"\n", merged)
+}
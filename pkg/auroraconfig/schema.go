@@ -0,0 +1,317 @@
+package auroraconfig
+
+import (
+	_ "embed" // embeds the default schema
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lithammer/fuzzysearch/fuzzy"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+)
+
+//go:embed schema/auroraconfig.json
+var defaultSchema []byte
+
+// Field types in a Schema
+const (
+	FieldTypeString = "string"
+	FieldTypeBool   = "bool"
+	FieldTypeInt    = "int"
+	FieldTypeFloat  = "float"
+	FieldTypeObject = "object"
+	FieldTypeList   = "list"
+)
+
+type (
+	// Schema describes the known keys of AuroraConfig files, with their types and allowed values
+	Schema struct {
+		Version string                  `json:"version"`
+		Fields  map[string]*FieldSchema `json:"fields"`
+	}
+
+	// FieldSchema describes a single key in an AuroraConfig file
+	FieldSchema struct {
+		Types     []string                `json:"types"`
+		Values    []string                `json:"values,omitempty"`
+		Fields    map[string]*FieldSchema `json:"fields,omitempty"`
+		AnyFields bool                    `json:"anyFields,omitempty"`
+	}
+
+	// Problem is a validation problem found in an AuroraConfig file
+	Problem struct {
+		File       string
+		Pointer    string
+		Message    string
+		Suggestion string
+	}
+)
+
+// String formats the problem as file, JSON pointer, message and suggestion
+func (p Problem) String() string {
+	text := fmt.Sprintf("%s: %s: %s", p.File, p.Pointer, p.Message)
+	if p.Suggestion != "" {
+		text += fmt.Sprintf(" (did you mean %s?)", p.Suggestion)
+	}
+	return text
+}
+
+// ParseSchema parses a schema in JSON format
+func ParseSchema(data []byte) (*Schema, error) {
+	var schema Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, errors.Wrap(err, "could not parse AuroraConfig schema")
+	}
+	if schema.Version == "" || len(schema.Fields) == 0 {
+		return nil, errors.New("AuroraConfig schema must have a version and fields")
+	}
+	return &schema, nil
+}
+
+// DefaultSchema returns the schema shipped with ao
+func DefaultSchema() *Schema {
+	schema, err := ParseSchema(defaultSchema)
+	if err != nil {
+		panic(err)
+	}
+	return schema
+}
+
+// LoadSchema loads the schema cached by WriteSchema, or the schema shipped with ao if there is no usable cached schema
+func LoadSchema(schemaLocation string) *Schema {
+	data, err := ioutil.ReadFile(schemaLocation)
+	if err != nil {
+		logrus.Debugf("Could not read optional file %s, using default schema: %v", schemaLocation, err)
+		return DefaultSchema()
+	}
+	schema, err := ParseSchema(data)
+	if err != nil {
+		logrus.Warnf("Could not use schema in %s, using default schema: %v", schemaLocation, err)
+		return DefaultSchema()
+	}
+	return schema
+}
+
+// WriteSchema parses a schema and caches it in schemaLocation, where it is used instead of the schema shipped with ao
+func WriteSchema(data []byte, schemaLocation string) (*Schema, error) {
+	schema, err := ParseSchema(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(schemaLocation, data, 0644); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// Validate validates the deploy spec files in an AuroraConfig against the schema.
+// Templates and the group file are not deploy specs, and are skipped.
+func (s *Schema) Validate(ac *AuroraConfig) []Problem {
+	var problems []Problem
+	for i := range ac.Files {
+		if !isDeploySpecFile(ac.Files[i].Name) {
+			continue
+		}
+		problems = append(problems, s.ValidateFile(&ac.Files[i])...)
+	}
+	return problems
+}
+
+func isDeploySpecFile(fileName string) bool {
+	if strings.HasPrefix(fileName, TemplateFolder+Separator) {
+		return false
+	}
+	return strings.TrimSuffix(fileName, path.Ext(fileName)) != GroupFile
+}
+
+// ValidateFile validates a single AuroraConfig file against the schema
+func (s *Schema) ValidateFile(file *File) []Problem {
+	content, err := file.parseContent()
+	if err != nil {
+		return []Problem{{File: file.Name, Pointer: "", Message: err.Error()}}
+	}
+	if content == nil {
+		return nil
+	}
+
+	root := &FieldSchema{Types: []string{FieldTypeObject}, Fields: s.Fields}
+	return root.validate(file.Name, "", content)
+}
+
+func (f *File) parseContent() (interface{}, error) {
+	var content interface{}
+	if f.IsYaml() {
+		if err := yaml.Unmarshal([]byte(f.Contents), &content); err != nil {
+			return nil, errors.Wrap(err, "invalid yaml")
+		}
+		return normalizeValue(content), nil
+	}
+	if err := json.Unmarshal([]byte(f.Contents), &content); err != nil {
+		return nil, errors.Wrap(err, "invalid json")
+	}
+	return content, nil
+}
+
+func (fs *FieldSchema) validate(fileName, pointer string, value interface{}) []Problem {
+	if !fs.acceptsType(value) {
+		return []Problem{{
+			File:    fileName,
+			Pointer: pointer,
+			Message: fmt.Sprintf("expected %s, got %s", strings.Join(fs.Types, " or "), typeOf(value)),
+		}}
+	}
+
+	if len(fs.Values) > 0 {
+		if text, ok := value.(string); ok && !containsString(fs.Values, text) {
+			return []Problem{{
+				File:       fileName,
+				Pointer:    pointer,
+				Message:    fmt.Sprintf("%s is not one of %s", text, strings.Join(fs.Values, ", ")),
+				Suggestion: closestMatch(text, fs.Values),
+			}}
+		}
+	}
+
+	object, ok := value.(map[string]interface{})
+	if !ok || fs.AnyFields {
+		return nil
+	}
+
+	var keys []string
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var known []string
+	for key := range fs.Fields {
+		known = append(known, key)
+	}
+
+	var problems []Problem
+	for _, key := range keys {
+		keyPointer := pointer + "/" + escapeJSONPointer(key)
+		field, exists := fs.Fields[key]
+		if !exists {
+			problems = append(problems, Problem{
+				File:       fileName,
+				Pointer:    keyPointer,
+				Message:    "unknown key " + key,
+				Suggestion: closestMatch(key, known),
+			})
+			continue
+		}
+		problems = append(problems, field.validate(fileName, keyPointer, object[key])...)
+	}
+	return problems
+}
+
+func (fs *FieldSchema) acceptsType(value interface{}) bool {
+	if len(fs.Types) == 0 {
+		return true
+	}
+	for _, fieldType := range fs.Types {
+		if isOfType(value, fieldType) {
+			return true
+		}
+	}
+	return false
+}
+
+// isOfType checks if a value is of a schema type. Like Boober, numbers and booleans may be given as strings,
+// and any scalar is accepted as a string.
+func isOfType(value interface{}, fieldType string) bool {
+	switch value.(type) {
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float64:
+		if fieldType == FieldTypeString {
+			return true
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		switch fieldType {
+		case FieldTypeString:
+			return true
+		case FieldTypeBool:
+			_, err := strconv.ParseBool(v)
+			return err == nil
+		case FieldTypeInt:
+			_, err := strconv.ParseInt(v, 10, 64)
+			return err == nil
+		case FieldTypeFloat:
+			_, err := strconv.ParseFloat(v, 64)
+			return err == nil
+		}
+	case bool:
+		return fieldType == FieldTypeBool
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fieldType == FieldTypeInt || fieldType == FieldTypeFloat
+	case float64:
+		return fieldType == FieldTypeFloat || (fieldType == FieldTypeInt && v == math.Trunc(v))
+	case map[string]interface{}:
+		return fieldType == FieldTypeObject
+	case []interface{}:
+		return fieldType == FieldTypeList
+	case nil:
+		return true
+	}
+	return false
+}
+
+func typeOf(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return FieldTypeString
+	case bool:
+		return FieldTypeBool
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return FieldTypeInt
+	case float64:
+		return FieldTypeFloat
+	case map[string]interface{}:
+		return FieldTypeObject
+	case []interface{}:
+		return FieldTypeList
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// closestMatch returns the candidate closest to text, if it is close enough to be a probable typo
+func closestMatch(text string, candidates []string) string {
+	best, bestDistance := "", math.MaxInt32
+	for _, candidate := range candidates {
+		distance := fuzzy.LevenshteinDistance(strings.ToLower(text), strings.ToLower(candidate))
+		if distance < bestDistance || (distance == bestDistance && candidate < best) {
+			best, bestDistance = candidate, distance
+		}
+	}
+	maxDistance := len(text) / 3
+	if maxDistance < 2 {
+		maxDistance = 2
+	}
+	if bestDistance > maxDistance {
+		return ""
+	}
+	return best
+}
+
+func escapeJSONPointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package auroraconfig
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_DefaultSchema(t *testing.T) {
+	schema := DefaultSchema()
+	assert.NotEmpty(t, schema.Version)
+	assert.Contains(t, schema.Fields, "replicas")
+}
+
+func Test_Schema_ValidateFile(t *testing.T) {
+	schema := DefaultSchema()
+
+	t.Run("Should accept valid json file", func(t *testing.T) {
+		file := File{Name: "utv/foo.json", Contents: `{
+			"baseFile": "foo.json",
+			"replicas": "2",
+			"pause": false,
+			"type": "deploy",
+			"route": true,
+			"config": {"ANY_KEY": {"nested": 1}},
+			"resources": {"cpu": {"min": "100m"}}
+		}`}
+		assert.Empty(t, schema.ValidateFile(&file))
+	})
+
+	t.Run("Should report unknown keys with suggestion", func(t *testing.T) {
+		file := File{Name: "utv/foo.yaml", Contents: "---\nreplcas: 2\nresources:\n  memory:\n    mx: 128Mi\n"}
+		problems := schema.ValidateFile(&file)
+		assert.Equal(t, []Problem{
+			{File: "utv/foo.yaml", Pointer: "/replcas", Message: "unknown key replcas", Suggestion: "replicas"},
+			{File: "utv/foo.yaml", Pointer: "/resources/memory/mx", Message: "unknown key mx", Suggestion: "max"},
+		}, problems)
+		assert.Equal(t, "utv/foo.yaml: /replcas: unknown key replcas (did you mean replicas?)", problems[0].String())
+	})
+
+	t.Run("Should report wrong types and values", func(t *testing.T) {
+		file := File{Name: "foo.json", Contents: `{"replicas": "two", "type": "deplyo", "permissions": "admin"}`}
+		problems := schema.ValidateFile(&file)
+		assert.Equal(t, []Problem{
+			{File: "foo.json", Pointer: "/permissions", Message: "expected object, got string"},
+			{File: "foo.json", Pointer: "/replicas", Message: "expected int, got string"},
+			{File: "foo.json", Pointer: "/type", Message: "deplyo is not one of deploy, development, template, localTemplate, cronjob, job", Suggestion: "deploy"},
+		}, problems)
+	})
+
+	t.Run("Should report invalid files", func(t *testing.T) {
+		file := File{Name: "foo.json", Contents: `{"replicas": 2`}
+		problems := schema.ValidateFile(&file)
+		assert.Len(t, problems, 1)
+		assert.Contains(t, problems[0].Message, "invalid json")
+	})
+
+	t.Run("Should escape JSON pointers", func(t *testing.T) {
+		file := File{Name: "foo.json", Contents: `{"a/b~c": 1}`}
+		problems := schema.ValidateFile(&file)
+		assert.Equal(t, "/a~1b~0c", problems[0].Pointer)
+	})
+}
+
+func Test_Schema_Validate(t *testing.T) {
+	ac := &AuroraConfig{Files: []File{
+		{Name: "about.json", Contents: `{"affiliation": "paas"}`},
+		{Name: "about-groups.yaml", Contents: "---\nfrontend: web api\n"},
+		{Name: "templates/about-web.json", Contents: `{"{{name}}": "{{value}}"}`},
+		{Name: "utv/foo.yaml", Contents: "---\nreplicas: 18446744073709551615\nreplcas: 2\n"},
+	}}
+
+	problems := DefaultSchema().Validate(ac)
+	assert.Equal(t, []Problem{
+		{File: "utv/foo.yaml", Pointer: "/replcas", Message: "unknown key replcas", Suggestion: "replicas"},
+	}, problems)
+}
+
+func Test_LoadSchema(t *testing.T) {
+	schemaLocation := filepath.Join(t.TempDir(), "schema.json")
+
+	assert.Equal(t, DefaultSchema().Version, LoadSchema(schemaLocation).Version)
+
+	_, err := WriteSchema([]byte(`{"version": "99"}`), schemaLocation)
+	assert.Error(t, err)
+	assert.Equal(t, DefaultSchema().Version, LoadSchema(schemaLocation).Version)
+
+	schema, err := WriteSchema([]byte(`{"version": "99", "fields": {"replicas": {"types": ["int"]}}}`), schemaLocation)
+	assert.NoError(t, err)
+	assert.Equal(t, "99", schema.Version)
+	assert.Equal(t, "99", LoadSchema(schemaLocation).Version)
+
+	assert.NoError(t, ioutil.WriteFile(schemaLocation, []byte("not json"), 0644))
+	assert.Equal(t, DefaultSchema().Version, LoadSchema(schemaLocation).Version)
+}
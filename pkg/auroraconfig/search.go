@@ -0,0 +1,146 @@
+package auroraconfig
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+type (
+	// SearchQuery selects values in AuroraConfig files by JSON pointer, by a regular expression on the value, or both
+	SearchQuery struct {
+		pointer []string
+		pattern *regexp.Regexp
+	}
+
+	// SearchMatch is a value in an AuroraConfig file matching a SearchQuery
+	SearchMatch struct {
+		File  string      `json:"file"`
+		Path  string      `json:"path"`
+		Value interface{} `json:"value"`
+	}
+)
+
+// NewSearchQuery creates a query matching values at or below pointer whose text matches pattern.
+// Segments of the pointer may contain wildcards such as "*". Empty pointer, the root pointer / or empty pattern matches anything.
+func NewSearchQuery(pointer, pattern string, ignoreCase bool) (*SearchQuery, error) {
+	query := &SearchQuery{}
+	if pointer != "" && pointer != "/" {
+		if !strings.HasPrefix(pointer, "/") {
+			return nil, errors.Errorf("%s is not a JSON pointer, it must start with /", pointer)
+		}
+		for _, segment := range strings.Split(strings.Trim(pointer, "/"), "/") {
+			segment = unescapeJSONPointer(segment)
+			if _, err := path.Match(segment, ""); err != nil {
+				return nil, errors.Wrapf(err, "invalid pointer %s", pointer)
+			}
+			query.pointer = append(query.pointer, segment)
+		}
+	}
+	if pattern != "" {
+		if ignoreCase {
+			pattern = "(?i)" + pattern
+		}
+		regex, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid pattern %s", pattern)
+		}
+		query.pattern = regex
+	}
+	return query, nil
+}
+
+// Search finds the values matching the query in the given files, in file order and in the order of keys in each file.
+// Files that can not be parsed are skipped with a warning.
+func (ac *AuroraConfig) Search(query *SearchQuery, fileNames []string) ([]SearchMatch, error) {
+	var matches []SearchMatch
+	for _, fileName := range fileNames {
+		file := ac.GetFile(fileName)
+		if file == nil {
+			return nil, errors.Errorf("could not find %s in AuroraConfig", fileName)
+		}
+		content, err := file.orderedContent()
+		if err != nil {
+			logrus.Warnf("Skipping %s: %v", fileName, err)
+			continue
+		}
+		matches = append(matches, query.search(file.Name, nil, content)...)
+	}
+	return matches, nil
+}
+
+func (q *SearchQuery) search(fileName string, segments []string, value interface{}) []SearchMatch {
+	if !q.couldMatchBelow(segments) {
+		return nil
+	}
+
+	switch v := value.(type) {
+	case *jsonObject:
+		if len(v.keys) > 0 {
+			var matches []SearchMatch
+			for _, key := range v.keys {
+				matches = append(matches, q.search(fileName, appendSegment(segments, key), v.values[key])...)
+			}
+			return matches
+		}
+	case []interface{}:
+		if len(v) > 0 {
+			var matches []SearchMatch
+			for i, item := range v {
+				matches = append(matches, q.search(fileName, appendSegment(segments, strconv.Itoa(i)), item)...)
+			}
+			return matches
+		}
+	}
+
+	if len(segments) < len(q.pointer) || (q.pattern != nil && !q.pattern.MatchString(searchText(value))) {
+		return nil
+	}
+	return []SearchMatch{{File: fileName, Path: jsonPointer(segments), Value: value}}
+}
+
+// couldMatchBelow checks if the segments so far match the beginning of the pointer of the query
+func (q *SearchQuery) couldMatchBelow(segments []string) bool {
+	for i, segment := range segments {
+		if i >= len(q.pointer) {
+			return true
+		}
+		if matched, _ := path.Match(q.pointer[i], segment); !matched {
+			return false
+		}
+	}
+	return true
+}
+
+func appendSegment(segments []string, segment string) []string {
+	return append(append([]string{}, segments...), segment)
+}
+
+func jsonPointer(segments []string) string {
+	var escaped []string
+	for _, segment := range segments {
+		escaped = append(escaped, escapeJSONPointer(segment))
+	}
+	return "/" + strings.Join(escaped, "/")
+}
+
+func unescapeJSONPointer(segment string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(segment, "~1", "/"), "~0", "~")
+}
+
+func searchText(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case *jsonObject:
+		return "{}"
+	case []interface{}:
+		return "[]"
+	}
+	return fmt.Sprint(value)
+}
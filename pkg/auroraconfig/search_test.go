@@ -0,0 +1,87 @@
+package auroraconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newSearchTestAuroraConfig() *AuroraConfig {
+	return &AuroraConfig{
+		Name: "paas",
+		Files: []File{
+			{Name: "about.json", Contents: `{"schemaVersion": "v1", "affiliation": "paas"}`},
+			{Name: "foo.json", Contents: `{"database": {"foo": "auto", "bar": "1234-abcd"}, "config": {"DB_URL": "jdbc:oracle:foo"}}`},
+			{Name: "utv/foo.yaml", Contents: "---\ndatabase:\n  foo: 5678-efgh\nsts: true\nconfig:\n  LIST:\n  - a\n  - b\n"},
+			{Name: "utv/broken.json", Contents: `{"database"`},
+		},
+	}
+}
+
+func Test_SearchByPointer(t *testing.T) {
+	ac := newSearchTestAuroraConfig()
+
+	query, err := NewSearchQuery("/database", "", false)
+	assert.NoError(t, err)
+	matches, err := ac.Search(query, ac.FileNames())
+	assert.NoError(t, err)
+	assert.Equal(t, []SearchMatch{
+		{File: "foo.json", Path: "/database/foo", Value: "auto"},
+		{File: "foo.json", Path: "/database/bar", Value: "1234-abcd"},
+		{File: "utv/foo.yaml", Path: "/database/foo", Value: "5678-efgh"},
+	}, matches)
+}
+
+func Test_SearchByRootPointer(t *testing.T) {
+	ac := newSearchTestAuroraConfig()
+
+	query, err := NewSearchQuery("/", "", false)
+	assert.NoError(t, err)
+	matches, err := ac.Search(query, []string{"about.json"})
+	assert.NoError(t, err)
+	assert.Equal(t, []SearchMatch{
+		{File: "about.json", Path: "/schemaVersion", Value: "v1"},
+		{File: "about.json", Path: "/affiliation", Value: "paas"},
+	}, matches)
+}
+
+func Test_SearchByPointerWithWildcard(t *testing.T) {
+	ac := newSearchTestAuroraConfig()
+
+	query, err := NewSearchQuery("/*/foo", "", false)
+	assert.NoError(t, err)
+	matches, err := ac.Search(query, []string{"utv/foo.yaml"})
+	assert.NoError(t, err)
+	assert.Equal(t, []SearchMatch{{File: "utv/foo.yaml", Path: "/database/foo", Value: "5678-efgh"}}, matches)
+}
+
+func Test_SearchByPattern(t *testing.T) {
+	ac := newSearchTestAuroraConfig()
+
+	query, err := NewSearchQuery("", "^TRUE$|ORACLE", true)
+	assert.NoError(t, err)
+	matches, err := ac.Search(query, ac.FileNames())
+	assert.NoError(t, err)
+	assert.Equal(t, []SearchMatch{
+		{File: "foo.json", Path: "/config/DB_URL", Value: "jdbc:oracle:foo"},
+		{File: "utv/foo.yaml", Path: "/sts", Value: true},
+	}, matches)
+}
+
+func Test_SearchByPointerAndPattern(t *testing.T) {
+	ac := newSearchTestAuroraConfig()
+
+	query, err := NewSearchQuery("/config", "^b$", false)
+	assert.NoError(t, err)
+	matches, err := ac.Search(query, ac.FileNames())
+	assert.NoError(t, err)
+	assert.Equal(t, []SearchMatch{{File: "utv/foo.yaml", Path: "/config/LIST/1", Value: "b"}}, matches)
+}
+
+func Test_NewSearchQueryErrors(t *testing.T) {
+	_, err := NewSearchQuery("database", "", false)
+	assert.EqualError(t, err, "database is not a JSON pointer, it must start with /")
+
+	_, err = NewSearchQuery("", "(", false)
+	assert.Error(t, err)
+}
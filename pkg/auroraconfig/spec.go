@@ -0,0 +1,408 @@
+package auroraconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/deploymentspec"
+)
+
+// Sources of values in a rendered spec that do not come from a file
+const (
+	SourceStatic     = "static"
+	SourceFileName   = "fileName"
+	SourceFolderName = "folderName"
+)
+
+const aboutFileName = "about"
+
+type (
+	// RenderedSpec is the effective configuration of an application deployment, merged locally from
+	// the files in an AuroraConfig. Keys are kept in the order they first appear.
+	RenderedSpec struct {
+		keys   []string
+		fields map[string]*RenderedField
+	}
+
+	// RenderedField is a value in a RenderedSpec with the file it came from.
+	// Objects have Fields instead of a value.
+	RenderedField struct {
+		Value   interface{}
+		Source  string
+		Sources []SpecSource
+		Fields  *RenderedSpec
+	}
+
+	// SpecSource is a file setting a value in a RenderedSpec
+	SpecSource struct {
+		Name  string      `json:"name"`
+		Value interface{} `json:"value"`
+	}
+)
+
+func newRenderedSpec() *RenderedSpec {
+	return &RenderedSpec{fields: make(map[string]*RenderedField)}
+}
+
+// SpecFiles returns the files that make up the configuration of an application deployment, in order of
+// increasing precedence: the global about file, the base file, the environment about file and the application file
+func (ac *AuroraConfig) SpecFiles(applicationDeploymentRef string) ([]*File, error) {
+	parts := strings.Split(applicationDeploymentRef, Separator)
+	if len(parts) != 2 {
+		return nil, errors.Errorf("%s is not a valid ApplicationDeploymentRef (environment/application)", applicationDeploymentRef)
+	}
+	env, app := parts[0], parts[1]
+
+	applicationFile, err := ac.findFileIgnoringExtension(applicationDeploymentRef)
+	if err != nil {
+		return nil, err
+	}
+	if applicationFile == nil {
+		return nil, errors.Errorf("could not find %s in AuroraConfig", applicationDeploymentRef)
+	}
+	applicationValues, err := applicationFile.referenceValues()
+	if err != nil {
+		return nil, err
+	}
+
+	baseFile, err := ac.findReferencedFile(applicationValues["baseFile"], app)
+	if err != nil {
+		return nil, err
+	}
+	envFile, err := ac.findReferencedFile(prefixFolder(env, applicationValues["envFile"]), path.Join(env, aboutFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	globalFileName := ""
+	if envFile != nil {
+		globalFileName, err = envFile.globalFileName()
+		if err != nil {
+			return nil, err
+		}
+	}
+	globalFile, err := ac.findReferencedFile(globalFileName, aboutFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*File
+	for _, file := range []*File{globalFile, baseFile, envFile, applicationFile} {
+		if file != nil {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// RenderSpec merges the files of an application deployment into the effective configuration
+func (ac *AuroraConfig) RenderSpec(applicationDeploymentRef string) (*RenderedSpec, error) {
+	files, err := ac.SpecFiles(applicationDeploymentRef)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.Split(applicationDeploymentRef, Separator)
+	spec := newRenderedSpec()
+	spec.set(nil, "applicationDeploymentRef", applicationDeploymentRef, SourceStatic)
+	spec.set(nil, "name", parts[1], SourceFileName)
+	spec.set(nil, "envName", parts[0], SourceFolderName)
+
+	for _, file := range files {
+		content, err := file.orderedContent()
+		if err != nil {
+			return nil, err
+		}
+		spec.merge(content, file.Name)
+	}
+
+	return spec, nil
+}
+
+// Get returns the field at a path such as "deployStrategy/type", or nil if it is not set
+func (s *RenderedSpec) Get(path string) *RenderedField {
+	current := s
+	parts := getPathParts(path)
+	for i, part := range parts {
+		field, ok := current.fields[part]
+		if !ok {
+			return nil
+		}
+		if i == len(parts)-1 {
+			return field
+		}
+		if field.Fields == nil {
+			return nil
+		}
+		current = field.Fields
+	}
+	return nil
+}
+
+// DeploymentSpec returns the rendered spec in the same structure as deploy specs from the Aurora API
+func (s *RenderedSpec) DeploymentSpec() deploymentspec.DeploymentSpec {
+	spec := make(deploymentspec.DeploymentSpec)
+	for _, key := range s.keys {
+		field := s.fields[key]
+		if field.Fields != nil {
+			spec[key] = map[string]interface{}(field.Fields.DeploymentSpec())
+			continue
+		}
+		spec[key] = map[string]interface{}{
+			"value":   field.Value,
+			"source":  field.Source,
+			"sources": field.Sources,
+		}
+	}
+	return spec
+}
+
+// Format returns the rendered spec as text, with the source of each value as a comment
+func (s *RenderedSpec) Format() string {
+	keyWidth := s.keyWidth(1)
+	valueWidth := s.valueWidth()
+
+	var lines []string
+	lines = append(lines, "{")
+	lines = append(lines, s.formatLines(1, keyWidth, valueWidth)...)
+	lines = append(lines, "}")
+	return strings.Join(lines, "\n")
+}
+
+func (s *RenderedSpec) formatLines(level, keyWidth, valueWidth int) []string {
+	indent := strings.Repeat("  ", level)
+	var lines []string
+	for _, key := range s.keys {
+		field := s.fields[key]
+		if field.Fields != nil {
+			lines = append(lines, fmt.Sprintf("%s%s: {", indent, key))
+			lines = append(lines, field.Fields.formatLines(level+1, keyWidth, valueWidth)...)
+			lines = append(lines, indent+"}")
+			continue
+		}
+		prefix := fmt.Sprintf("%-*s", keyWidth, indent+key+":")
+		lines = append(lines, fmt.Sprintf("%s %-*s // %s", prefix, valueWidth, formatSpecValue(field.Value), field.Source))
+	}
+	return lines
+}
+
+func (s *RenderedSpec) keyWidth(level int) int {
+	width := 0
+	for _, key := range s.keys {
+		field := s.fields[key]
+		if field.Fields != nil {
+			if nested := field.Fields.keyWidth(level + 1); nested > width {
+				width = nested
+			}
+			continue
+		}
+		if keyLength := 2*level + len(key) + 1; keyLength > width {
+			width = keyLength
+		}
+	}
+	return width
+}
+
+func (s *RenderedSpec) valueWidth() int {
+	width := 0
+	for _, key := range s.keys {
+		field := s.fields[key]
+		if field.Fields != nil {
+			if nested := field.Fields.valueWidth(); nested > width {
+				width = nested
+			}
+			continue
+		}
+		if valueLength := len(formatSpecValue(field.Value)); valueLength > width {
+			width = valueLength
+		}
+	}
+	return width
+}
+
+func formatSpecValue(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+func (s *RenderedSpec) merge(content *jsonObject, source string) {
+	for _, key := range content.keys {
+		value := content.values[key]
+		if object, ok := value.(*jsonObject); ok {
+			field, exists := s.fields[key]
+			if !exists || field.Fields == nil {
+				field = &RenderedField{Fields: newRenderedSpec()}
+				s.put(key, field)
+			}
+			field.Fields.merge(object, source)
+			continue
+		}
+		s.set(s.fields[key], key, value, source)
+	}
+}
+
+// set sets a leaf value. Earlier sources of the same leaf are kept in Sources.
+func (s *RenderedSpec) set(existing *RenderedField, key string, value interface{}, source string) {
+	var sources []SpecSource
+	if existing != nil && existing.Fields == nil {
+		sources = existing.Sources
+	}
+	s.put(key, &RenderedField{
+		Value:   value,
+		Source:  source,
+		Sources: append(sources, SpecSource{Name: source, Value: value}),
+	})
+}
+
+func (s *RenderedSpec) put(key string, field *RenderedField) {
+	if _, exists := s.fields[key]; !exists {
+		s.keys = append(s.keys, key)
+	}
+	s.fields[key] = field
+}
+
+// orderedContent parses the file into an object keeping the order of keys
+func (f *File) orderedContent() (*jsonObject, error) {
+	if f.IsYaml() {
+		document, err := unmarshalYamlFile(f)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not parse %s", f.Name)
+		}
+		return yamlToJSONValue(yamlRootMapping(document)).(*jsonObject), nil
+	}
+
+	document, err := jsonToYamlDocument(f.Contents)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not parse %s", f.Name)
+	}
+	object, ok := yamlToJSONValue(document.Content[0]).(*jsonObject)
+	if !ok {
+		return nil, errors.Errorf("%s must contain a json object", f.Name)
+	}
+	return object, nil
+}
+
+func (f *File) globalFileName() (string, error) {
+	content, err := f.parseContent()
+	if err != nil {
+		return "", errors.Wrapf(err, "could not parse %s", f.Name)
+	}
+	object, _ := content.(map[string]interface{})
+	globalFile, _ := object["globalFile"].(string)
+	return globalFile, nil
+}
+
+// defaultReferences returns the baseFile, envFile and globalFile keys left out of the files of an application deployment,
+// where a file is used by default. The Value of each reference is the name of the file used.
+func (ac *AuroraConfig) defaultReferences(applicationDeploymentRef string) ([]Reference, error) {
+	parts := strings.Split(applicationDeploymentRef, Separator)
+	if len(parts) != 2 {
+		return nil, errors.Errorf("%s is not a valid ApplicationDeploymentRef (environment/application)", applicationDeploymentRef)
+	}
+	env, app := parts[0], parts[1]
+
+	applicationFile, err := ac.findFileIgnoringExtension(applicationDeploymentRef)
+	if err != nil {
+		return nil, err
+	}
+	if applicationFile == nil {
+		return nil, errors.Errorf("could not find %s in AuroraConfig", applicationDeploymentRef)
+	}
+	applicationValues, err := applicationFile.referenceValues()
+	if err != nil {
+		return nil, err
+	}
+
+	var references []Reference
+	if applicationValues["baseFile"] == "" {
+		baseFile, err := ac.findFileIgnoringExtension(app)
+		if err != nil {
+			return nil, err
+		}
+		if baseFile != nil {
+			references = append(references, Reference{File: applicationFile.Name, Key: "baseFile", Value: baseFile.Name})
+		}
+	}
+	envFile, err := ac.findReferencedFile(prefixFolder(env, applicationValues["envFile"]), path.Join(env, aboutFileName))
+	if err != nil {
+		return nil, err
+	}
+	if envFile != nil && applicationValues["envFile"] == "" {
+		references = append(references, Reference{File: applicationFile.Name, Key: "envFile", Value: envFile.Name})
+	}
+	if envFile != nil {
+		globalFileName, err := envFile.globalFileName()
+		if err != nil {
+			return nil, err
+		}
+		globalFile, err := ac.findFileIgnoringExtension(aboutFileName)
+		if err != nil {
+			return nil, err
+		}
+		if globalFileName == "" && globalFile != nil {
+			references = append(references, Reference{File: envFile.Name, Key: "globalFile", Value: globalFile.Name})
+		}
+	}
+	return references, nil
+}
+
+// findReferencedFile finds a file referred to by name. If name is empty, the file defaultName is used if it exists.
+func (ac *AuroraConfig) findReferencedFile(name, defaultName string) (*File, error) {
+	if name == "" {
+		return ac.findFileIgnoringExtension(defaultName)
+	}
+	file, err := ac.findFileIgnoringExtension(name)
+	if err != nil {
+		return nil, err
+	}
+	if file == nil {
+		return nil, errors.Errorf("could not find referenced file %s in AuroraConfig", name)
+	}
+	return file, nil
+}
+
+// findFileIgnoringExtension finds a file by name, where both json and yaml files match.
+// It is an error if the name matches more than one file, and none of them exactly.
+func (ac *AuroraConfig) findFileIgnoringExtension(name string) (*File, error) {
+	withoutExtension := trimFileExtension(name)
+	var found []*File
+	for i := range ac.Files {
+		fileName := ac.Files[i].Name
+		if fileName == name {
+			return &ac.Files[i], nil
+		}
+		if trimFileExtension(fileName) == withoutExtension {
+			found = append(found, &ac.Files[i])
+		}
+	}
+	if len(found) > 1 {
+		return nil, errors.Errorf("%s is ambiguous, it matches both %s and %s", name, found[0].Name, found[1].Name)
+	}
+	if len(found) == 1 {
+		return found[0], nil
+	}
+	return nil, nil
+}
+
+// trimFileExtension removes a json or yaml extension from name
+func trimFileExtension(name string) string {
+	for _, extension := range []string{".json", ".yaml", ".yml"} {
+		if strings.HasSuffix(strings.ToLower(name), extension) {
+			return name[:len(name)-len(extension)]
+		}
+	}
+	return name
+}
+
+func prefixFolder(folder, name string) string {
+	if name == "" {
+		return ""
+	}
+	return path.Join(folder, name)
+}
@@ -0,0 +1,120 @@
+package auroraconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newSpecTestAuroraConfig() *AuroraConfig {
+	return &AuroraConfig{
+		Name: "paas",
+		Files: []File{
+			{Name: "about.json", Contents: `{"schemaVersion": "v1", "affiliation": "paas", "permissions": {"admin": "APP_PaaS_drift"}, "certificate": true}`},
+			{Name: "redis.json", Contents: `{"type": "template", "template": "redis", "parameters": {"APP_NAME": "redis", "AFFILIATION": "paas"}}`},
+			{Name: "aotest/about.json", Contents: `{"cluster": "utv"}`},
+			{Name: "aotest/about-template.yaml", Contents: "---\ncluster: utv01 # template cluster\nparameters:\n  AFFILIATION: paas2\n"},
+			{Name: "aotest/redis.json", Contents: `{"envFile": "about-template.json", "route": true}`},
+			{Name: "aotest/other.yaml", Contents: "---\nbaseFile: redis.yaml\nreplicas: 2\n"},
+			{Name: "aotest/broken.json", Contents: `{"baseFile": "missing.json"}`},
+		},
+	}
+}
+
+func Test_SpecFiles(t *testing.T) {
+	ac := newSpecTestAuroraConfig()
+
+	files, err := ac.SpecFiles("aotest/redis")
+	assert.NoError(t, err)
+	var names []string
+	for _, file := range files {
+		names = append(names, file.Name)
+	}
+	assert.Equal(t, []string{"about.json", "redis.json", "aotest/about-template.yaml", "aotest/redis.json"}, names)
+
+	files, err = ac.SpecFiles("aotest/other")
+	assert.NoError(t, err)
+	assert.Equal(t, "redis.json", files[1].Name)
+	assert.Equal(t, "aotest/about.json", files[2].Name)
+
+	_, err = ac.SpecFiles("aotest/broken")
+	assert.EqualError(t, err, "could not find referenced file missing.json in AuroraConfig")
+
+	_, err = ac.SpecFiles("aotest/missing")
+	assert.Error(t, err)
+
+	_, err = ac.SpecFiles("redis")
+	assert.Error(t, err)
+}
+
+func Test_findFileIgnoringExtension(t *testing.T) {
+	ac := &AuroraConfig{
+		Name: "paas",
+		Files: []File{
+			{Name: "app.json", Contents: `{}`},
+			{Name: "both.json", Contents: `{}`},
+			{Name: "both.yaml", Contents: "---\n"},
+		},
+	}
+
+	file, err := ac.findFileIgnoringExtension("app.yml")
+	assert.NoError(t, err)
+	assert.Equal(t, "app.json", file.Name)
+
+	file, err = ac.findFileIgnoringExtension("app.v2")
+	assert.NoError(t, err)
+	assert.Nil(t, file)
+
+	file, err = ac.findFileIgnoringExtension("both.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, "both.yaml", file.Name)
+
+	_, err = ac.findFileIgnoringExtension("both")
+	assert.EqualError(t, err, "both is ambiguous, it matches both both.json and both.yaml")
+}
+
+func Test_RenderSpec(t *testing.T) {
+	ac := newSpecTestAuroraConfig()
+
+	spec, err := ac.RenderSpec("aotest/redis")
+	assert.NoError(t, err)
+
+	cluster := spec.Get("cluster")
+	assert.Equal(t, "utv01", cluster.Value)
+	assert.Equal(t, "aotest/about-template.yaml", cluster.Source)
+
+	affiliation := spec.Get("parameters/AFFILIATION")
+	assert.Equal(t, "paas2", affiliation.Value)
+	assert.Equal(t, []SpecSource{{Name: "redis.json", Value: "paas"}, {Name: "aotest/about-template.yaml", Value: "paas2"}}, affiliation.Sources)
+
+	assert.Nil(t, spec.Get("replicas"))
+	assert.Nil(t, spec.Get("cluster/foo"))
+
+	deploymentSpec := spec.DeploymentSpec()
+	assert.Equal(t, "aotest", deploymentSpec.Environment())
+	assert.Equal(t, "redis", deploymentSpec.Name())
+	assert.Equal(t, "utv01", deploymentSpec.Cluster())
+	assert.Equal(t, "APP_PaaS_drift", deploymentSpec.GetString("permissions/admin"))
+
+	expected := `{
+  applicationDeploymentRef: "aotest/redis"        // static
+  name:                     "redis"               // fileName
+  envName:                  "aotest"              // folderName
+  schemaVersion:            "v1"                  // about.json
+  affiliation:              "paas"                // about.json
+  permissions: {
+    admin:                  "APP_PaaS_drift"      // about.json
+  }
+  certificate:              true                  // about.json
+  type:                     "template"            // redis.json
+  template:                 "redis"               // redis.json
+  parameters: {
+    APP_NAME:               "redis"               // redis.json
+    AFFILIATION:            "paas2"               // aotest/about-template.yaml
+  }
+  cluster:                  "utv01"               // aotest/about-template.yaml
+  envFile:                  "about-template.json" // aotest/redis.json
+  route:                    true                  // aotest/redis.json
+}`
+	assert.Equal(t, expected, spec.Format())
+}
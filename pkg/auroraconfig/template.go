@@ -0,0 +1,191 @@
+package auroraconfig
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+//go:embed templates
+var defaultTemplates embed.FS
+
+// Kinds of templates
+const (
+	TemplateApp = "app"
+	TemplateEnv = "env"
+)
+
+// TemplateFolder is the folder in an AuroraConfig holding templates. Template files start with "about-",
+// so they are not mistaken for applications.
+const TemplateFolder = "templates"
+
+// Fields every template is rendered with, that are not asked for
+const (
+	TemplateFieldName = "name"
+	TemplateFieldEnv  = "env"
+)
+
+// templateTargets are the names of template files by kind, and the names of the files they are rendered to
+var templateTargets = map[string][][2]string{
+	TemplateApp: {
+		{"about-app-base", "{{.name}}"},
+		{"about-app", "{{.env}}/{{.name}}"},
+	},
+	TemplateEnv: {
+		{"about-env", "{{.env}}/about"},
+	},
+}
+
+var templateFieldPattern = regexp.MustCompile(`\{\{\s*\.(\w+)\s*\}\}`)
+
+type (
+	// Template is a set of files used to create new applications or environments.
+	// Values such as {{.name}} and {{.cluster}} are replaced when the template is rendered.
+	Template struct {
+		Kind  string
+		Files []TemplateFile
+	}
+
+	// TemplateFile is a file in a Template
+	TemplateFile struct {
+		Source   string
+		Target   string
+		Contents string
+		Format   string
+	}
+)
+
+// DefaultTemplate returns the template of the given kind shipped with ao
+func DefaultTemplate(kind string) (*Template, error) {
+	entries, err := defaultTemplates.ReadDir("templates")
+	if err != nil {
+		return nil, err
+	}
+	var files []File
+	for _, entry := range entries {
+		data, err := defaultTemplates.ReadFile(path.Join("templates", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, File{Name: entry.Name(), Contents: string(data)})
+	}
+	return LoadTemplate(kind, files)
+}
+
+// LoadTemplate creates a template of the given kind from files named about-app-base, about-app or about-env,
+// with a json or yaml extension. Folders in file names are ignored. It returns nil if no template files are found.
+func LoadTemplate(kind string, files []File) (*Template, error) {
+	targets, ok := templateTargets[kind]
+	if !ok {
+		return nil, errors.Errorf("unknown template kind %s", kind)
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name < files[j].Name
+	})
+
+	tmpl := &Template{Kind: kind}
+	for _, target := range targets {
+		for _, file := range files {
+			baseName := path.Base(file.Name)
+			if strings.TrimSuffix(baseName, path.Ext(baseName)) != target[0] {
+				continue
+			}
+			format := strings.TrimPrefix(strings.ToLower(path.Ext(baseName)), ".")
+			if format == "yml" {
+				format = FormatYaml
+			}
+			if format != FormatJSON && format != FormatYaml {
+				continue
+			}
+			tmpl.Files = append(tmpl.Files, TemplateFile{
+				Source:   file.Name,
+				Target:   target[1],
+				Contents: file.Contents,
+				Format:   format,
+			})
+			break
+		}
+	}
+
+	if len(tmpl.Files) == 0 {
+		return nil, nil
+	}
+	return tmpl, nil
+}
+
+// TemplateFiles returns the templates in the templates folder of the AuroraConfig
+func (ac *AuroraConfig) TemplateFiles() []File {
+	var files []File
+	for _, file := range ac.Files {
+		if strings.HasPrefix(file.Name, TemplateFolder+Separator) {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// Fields returns the names of the values used in the template, in order of first use, except name and env
+func (t *Template) Fields() []string {
+	var fields []string
+	seen := map[string]bool{TemplateFieldName: true, TemplateFieldEnv: true}
+	for _, file := range t.Files {
+		for _, match := range templateFieldPattern.FindAllStringSubmatch(file.Contents, -1) {
+			if !seen[match[1]] {
+				seen[match[1]] = true
+				fields = append(fields, match[1])
+			}
+		}
+	}
+	return fields
+}
+
+// Render creates the files of the template with the given values. Values are escaped as strings in JSON,
+// which also works in double quoted YAML strings. Rendered files must be valid JSON or YAML.
+func (t *Template) Render(values map[string]string) ([]File, error) {
+	escaped := make(map[string]string)
+	for key, value := range values {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		escaped[key] = strings.TrimSuffix(strings.TrimPrefix(string(data), `"`), `"`)
+	}
+
+	var files []File
+	for _, templateFile := range t.Files {
+		name, err := renderTemplate(templateFile.Source+" (name)", templateFile.Target, values)
+		if err != nil {
+			return nil, err
+		}
+		contents, err := renderTemplate(templateFile.Source, templateFile.Contents, escaped)
+		if err != nil {
+			return nil, err
+		}
+		file := File{Name: name + "." + templateFile.Format, Contents: contents}
+		if _, err := file.parseContent(); err != nil {
+			return nil, errors.Wrapf(err, "template %s rendered to an invalid file", templateFile.Source)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+func renderTemplate(name, text string, values map[string]string) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid template %s", name)
+	}
+	var buffer bytes.Buffer
+	if err := tmpl.Execute(&buffer, values); err != nil {
+		return "", errors.Wrapf(err, "could not render template %s", name)
+	}
+	return buffer.String(), nil
+}
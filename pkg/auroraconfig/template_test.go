@@ -0,0 +1,58 @@
+package auroraconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_DefaultTemplate(t *testing.T) {
+	tmpl, err := DefaultTemplate(TemplateApp)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"groupId", "version"}, tmpl.Fields())
+
+	files, err := tmpl.Render(map[string]string{"name": "foo", "env": "utv", "groupId": "no.skatteetaten", "version": "1.0"})
+	assert.NoError(t, err)
+	assert.Len(t, files, 2)
+	assert.Equal(t, "foo.json", files[0].Name)
+	assert.Contains(t, files[0].Contents, `"artifactId": "foo"`)
+	assert.Equal(t, "utv/foo.json", files[1].Name)
+	assert.Contains(t, files[1].Contents, `"version": "1.0"`)
+
+	tmpl, err = DefaultTemplate(TemplateEnv)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"cluster", "admin"}, tmpl.Fields())
+}
+
+func Test_LoadTemplateFromAuroraConfig(t *testing.T) {
+	ac := &AuroraConfig{Files: []File{
+		{Name: "about.json", Contents: `{}`},
+		{Name: "templates/about-env.yaml", Contents: "---\ncluster: \"{{.cluster}}\"\nsegment: \"{{.segment}}\"\n"},
+	}}
+
+	tmpl, err := LoadTemplate(TemplateApp, ac.TemplateFiles())
+	assert.NoError(t, err)
+	assert.Nil(t, tmpl)
+
+	tmpl, err = LoadTemplate(TemplateEnv, ac.TemplateFiles())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"cluster", "segment"}, tmpl.Fields())
+
+	files, err := tmpl.Render(map[string]string{"env": "utv", "cluster": "utv04", "segment": `a "quoted" segment`})
+	assert.NoError(t, err)
+	assert.Equal(t, []File{{Name: "utv/about.yaml", Contents: "---\ncluster: \"utv04\"\nsegment: \"a \\\"quoted\\\" segment\"\n"}}, files)
+}
+
+func Test_RenderTemplateErrors(t *testing.T) {
+	tmpl, err := LoadTemplate(TemplateEnv, []File{{Name: "about-env.json", Contents: `{"cluster": {{.cluster}}}`}})
+	assert.NoError(t, err)
+
+	_, err = tmpl.Render(map[string]string{"env": "utv"})
+	assert.Error(t, err)
+
+	_, err = tmpl.Render(map[string]string{"env": "utv", "cluster": "utv04"})
+	assert.Contains(t, err.Error(), "template about-env.json rendered to an invalid file")
+
+	_, err = LoadTemplate("database", nil)
+	assert.EqualError(t, err, "unknown template kind database")
+}
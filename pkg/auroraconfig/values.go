@@ -0,0 +1,88 @@
+package auroraconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// Supported value types when setting values in an AuroraConfigFile
+const (
+	ValueTypeString = "string"
+	ValueTypeBool   = "bool"
+	ValueTypeInt    = "int"
+	ValueTypeFloat  = "float"
+	ValueTypeJSON   = "json"
+	ValueTypeYaml   = "yaml"
+)
+
+// ValueTypes lists all supported value types
+var ValueTypes = []string{ValueTypeString, ValueTypeBool, ValueTypeInt, ValueTypeFloat, ValueTypeJSON, ValueTypeYaml}
+
+// ParseValue parses a value given on the command line according to valueType
+func ParseValue(value string, valueType string) (interface{}, error) {
+	switch strings.ToLower(valueType) {
+	case "", ValueTypeString:
+		return value, nil
+	case ValueTypeBool:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, errors.Errorf("%s is not a valid bool", value)
+		}
+		return parsed, nil
+	case ValueTypeInt:
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, errors.Errorf("%s is not a valid int", value)
+		}
+		return parsed, nil
+	case ValueTypeFloat:
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, errors.Errorf("%s is not a valid float", value)
+		}
+		return parsed, nil
+	case ValueTypeJSON:
+		var parsed interface{}
+		if err := json.Unmarshal([]byte(value), &parsed); err != nil {
+			return nil, errors.Wrap(err, "value is not valid json")
+		}
+		return parsed, nil
+	case ValueTypeYaml:
+		var parsed interface{}
+		if err := yaml.Unmarshal([]byte(value), &parsed); err != nil {
+			return nil, errors.Wrap(err, "value is not valid yaml")
+		}
+		return normalizeValue(parsed), nil
+	}
+
+	return nil, errors.Errorf("unknown value type %s, must be one of %s", valueType, strings.Join(ValueTypes, "|"))
+}
+
+// normalizeValue converts maps with interface keys, as produced by yaml.v2, to maps with string keys
+// so the value can be marshalled both as JSON and YAML
+func normalizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		normalized := make(map[string]interface{}, len(v))
+		for key, subValue := range v {
+			normalized[fmt.Sprint(key)] = normalizeValue(subValue)
+		}
+		return normalized
+	case map[string]interface{}:
+		for key, subValue := range v {
+			v[key] = normalizeValue(subValue)
+		}
+		return v
+	case []interface{}:
+		for i, subValue := range v {
+			v[i] = normalizeValue(subValue)
+		}
+		return v
+	}
+	return value
+}
@@ -0,0 +1,48 @@
+package auroraconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseValue(t *testing.T) {
+	cases := []struct {
+		Value    string
+		Type     string
+		Expected interface{}
+	}{
+		{"true", "", "true"},
+		{"007", ValueTypeString, "007"},
+		{"true", ValueTypeBool, true},
+		{"3", ValueTypeInt, int64(3)},
+		{"0.5", ValueTypeFloat, 0.5},
+		{`["a", 1]`, ValueTypeJSON, []interface{}{"a", float64(1)}},
+		{"a:\n  b: 1", ValueTypeYaml, map[string]interface{}{"a": map[string]interface{}{"b": 1}}},
+	}
+
+	for _, tc := range cases {
+		value, err := ParseValue(tc.Value, tc.Type)
+		assert.Nil(t, err)
+		assert.Equal(t, tc.Expected, value)
+	}
+}
+
+func Test_ParseValue_Invalid(t *testing.T) {
+	cases := []struct {
+		Value string
+		Type  string
+	}{
+		{"yes please", ValueTypeBool},
+		{"1.5", ValueTypeInt},
+		{"abc", ValueTypeFloat},
+		{"{", ValueTypeJSON},
+		{"a: [", ValueTypeYaml},
+		{"1", "date"},
+	}
+
+	for _, tc := range cases {
+		_, err := ParseValue(tc.Value, tc.Type)
+		assert.NotNil(t, err, "%s as %s", tc.Value, tc.Type)
+	}
+}
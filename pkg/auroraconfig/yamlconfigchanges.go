@@ -1,49 +1,55 @@
 package auroraconfig
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
 	"github.com/sirupsen/logrus"
-	"gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
 )
 
-// RemoveEntry removes a value in an AuroraConfigFile on specified path
-func yamlRemoveEntry(auroraConfigFile *File, pathParts []string) error {
+const (
+	yamlFileDashes    = "---\n"
+	yamlDefaultIndent = 2
+)
 
-	var yamlcontent map[interface{}]interface{}
-	// Unmarshal content from configfile
-	if err := unmarshalYamlFile(auroraConfigFile, &yamlcontent); err != nil {
-		return err
-	}
+// The YAML change engine works on yaml.v3 nodes instead of maps, so that comments, key order,
+// quoting style and anchors survive a change. Only the node at the given path is touched.
 
-	// Call the recursive parsing of content to locate and remove the entry
-	if err := yamlRemoveEntryRecursive(&yamlcontent, pathParts); err != nil {
-		return err
-	}
-
-	// Marshal changed content prettyfied back into configfile
-	if err := marshalYamlFile(auroraConfigFile, &yamlcontent); err != nil {
-		return err
-	}
+// RemoveEntry removes a value in an AuroraConfigFile on specified path
+func yamlRemoveEntry(auroraConfigFile *File, pathParts []string) error {
 
-	return nil
+	return yamlChangeFile(auroraConfigFile, func(document *yaml.Node) error {
+		// Call the recursive parsing of content to locate and remove the entry
+		return yamlRemoveEntryRecursive(yamlRootMapping(document), pathParts)
+	})
 }
 
-func yamlRemoveEntryRecursive(yamlContent *map[interface{}]interface{}, pathParts []string) error {
+func yamlRemoveEntryRecursive(mapping *yaml.Node, pathParts []string) error {
 	firstOfPath, err := validateAndGetFirstOfPath(pathParts)
 	if err != nil {
 		return err
 	}
 
-	_, entryExists := (*yamlContent)[firstOfPath].(interface{})
-	if !entryExists {
+	index := yamlFindKey(mapping, firstOfPath)
+	if index < 0 {
 		return errors.New("No such path in target YAML document")
 	}
 	if len(pathParts) == 1 {
-		delete((*yamlContent), firstOfPath)
+		mapping.Content = append(mapping.Content[:index], mapping.Content[index+2:]...)
 	} else {
 		restOfPath := pathParts[1:]
-		subContent := (*yamlContent)[firstOfPath].(map[interface{}]interface{})
-		if err := yamlRemoveEntryRecursive(&subContent, restOfPath); err != nil {
+		subContent := mapping.Content[index+1]
+		if subContent.Kind == yaml.AliasNode {
+			return fmt.Errorf("Path goes through alias *%s, change the anchored value instead", subContent.Value)
+		}
+		if subContent.Kind != yaml.MappingNode {
+			return errors.New("No such path in target YAML document")
+		}
+		if err := yamlRemoveEntryRecursive(subContent, restOfPath); err != nil {
 			return err
 		}
 	}
@@ -51,76 +57,358 @@ func yamlRemoveEntryRecursive(yamlContent *map[interface{}]interface{}, pathPart
 	return nil
 }
 
-func yamlSetValue(auroraConfigFile *File, pathParts []string, value string) error {
+func yamlSetValue(auroraConfigFile *File, pathParts []string, value interface{}) error {
+
+	return yamlChangeFile(auroraConfigFile, func(document *yaml.Node) error {
+		// Call the recursive parsing of content to locate and set the value
+		return yamlSetOrCreateRecursive(yamlRootMapping(document), pathParts, value)
+	})
+}
 
-	var yamlcontent map[interface{}]interface{}
-	// Unmarshal content from file
-	if err := unmarshalYamlFile(auroraConfigFile, &yamlcontent); err != nil {
+// yamlChangeFile applies change to the document in auroraConfigFile. Only the lines that differ when the
+// document is marshalled before and after the change are replaced, so blank lines, comment alignment and
+// other formatting in the rest of the file is kept as it is.
+func yamlChangeFile(auroraConfigFile *File, change func(document *yaml.Node) error) error {
+	document, err := unmarshalYamlFile(auroraConfigFile)
+	if err != nil {
 		return err
 	}
-	if len(yamlcontent) == 0 {
-		yamlcontent = make(map[interface{}]interface{})
+
+	// The comments of a document without content are kept as they are, and the new content added after them
+	if yamlOnlyComments(auroraConfigFile.Contents) {
+		empty := &yaml.Node{}
+		if err := change(empty); err != nil {
+			return err
+		}
+		changed, err := renderYaml(empty)
+		if err != nil {
+			return err
+		}
+		contents := auroraConfigFile.Contents
+		if !strings.HasSuffix(contents, "\n") {
+			contents += "\n"
+		}
+		auroraConfigFile.Contents = contents + strings.TrimPrefix(changed, yamlFileDashes)
+		return nil
 	}
 
-	// Call the recursive parsing of content to locate and set the value
-	if err := yamlSetOrCreateRecursive(&yamlcontent, pathParts, value); err != nil {
-		return err
+	var rendered string
+	if document.Kind == yaml.DocumentNode && len(document.Content) > 0 && document.Content[0].Kind == yaml.MappingNode {
+		if rendered, err = renderYaml(document); err != nil {
+			return err
+		}
 	}
 
-	// Marshal changed content prettyfied back into file
-	if err := marshalYamlFile(auroraConfigFile, &yamlcontent); err != nil {
+	if err := change(document); err != nil {
 		return err
 	}
 
+	changed, err := renderYaml(document)
+	if err != nil {
+		return err
+	}
+	if rendered == "" {
+		auroraConfigFile.Contents = changed
+		return nil
+	}
+	auroraConfigFile.Contents = spliceLines(auroraConfigFile.Contents, rendered, changed)
 	return nil
 }
 
-func yamlSetOrCreateRecursive(content *map[interface{}]interface{}, pathParts []string, value string) error {
+// spliceLines applies the line changes from rendered to changed to original, where rendered is original
+// in another formatting. Original lines that are not changed are kept as they are.
+func spliceLines(original, rendered, changed string) string {
+	if !strings.HasSuffix(original, "\n") {
+		original += "\n"
+	}
+	originalLines, renderedLines, changedLines := diffLines(original), diffLines(rendered), diffLines(changed)
+	// Lines are matched ignoring whitespace after the indentation, since the marshalled document aligns comments differently
+	matches := matchLines(normalizeLines(renderedLines), normalizeLines(originalLines))
+
+	// after returns the index in original following the original line of rendered line i
+	after := func(i int) int {
+		for ; i >= 0; i-- {
+			if matches[i] >= 0 {
+				return matches[i] + 1
+			}
+		}
+		return 0
+	}
+	// at returns the index in original of the original line of rendered line i
+	at := func(i int) int {
+		for ; i < len(renderedLines); i++ {
+			if matches[i] >= 0 {
+				return matches[i]
+			}
+		}
+		return len(originalLines)
+	}
+
+	var spliced []string
+	next := 0
+	for _, op := range difflib.NewMatcherWithJunk(renderedLines, changedLines, false, nil).GetOpCodes() {
+		if op.Tag == 'e' {
+			continue
+		}
+		start, end := after(op.I1-1), after(op.I1-1)
+		if op.I2 > op.I1 {
+			start = at(op.I1)
+			if matches[op.I1] < 0 {
+				start = after(op.I1 - 1)
+			}
+			end = after(op.I2 - 1)
+			if matches[op.I2-1] < 0 {
+				end = at(op.I2)
+			}
+		}
+		if start < next {
+			start = next
+		}
+		if end < start {
+			end = start
+		}
+		spliced = append(spliced, originalLines[next:start]...)
+		spliced = append(spliced, changedLines[op.J1:op.J2]...)
+		next = end
+	}
+	spliced = append(spliced, originalLines[next:]...)
+	return strings.Join(spliced, "")
+}
+
+// yamlOnlyComments returns true if contents has comments, and nothing but comments, blank lines and document markers
+func yamlOnlyComments(contents string) bool {
+	comments := false
+	for _, line := range strings.Split(contents, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "#") {
+			comments = true
+		} else if line != "" && line != "---" {
+			return false
+		}
+	}
+	return comments
+}
+
+// diffLines splits contents into lines that all end with a newline
+func diffLines(contents string) []string {
+	if contents == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(contents, "\n")
+	if lines[len(lines)-1] == "" {
+		return lines[:len(lines)-1]
+	}
+	lines[len(lines)-1] += "\n"
+	return lines
+}
+
+// matchLines maps each line in base to the index of the same line in other, or -1 if it is changed
+func matchLines(base, other []string) []int {
+	matches := make([]int, len(base))
+	for i := range matches {
+		matches[i] = -1
+	}
+	for _, block := range difflib.NewMatcherWithJunk(base, other, false, nil).GetMatchingBlocks() {
+		for i := 0; i < block.Size; i++ {
+			matches[block.A+i] = block.B + i
+		}
+	}
+	return matches
+}
+
+func normalizeLines(lines []string) []string {
+	normalized := make([]string, len(lines))
+	for i, line := range lines {
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		normalized[i] = indent + strings.Join(strings.Fields(line), " ")
+	}
+	return normalized
+}
+
+func yamlSetOrCreateRecursive(mapping *yaml.Node, pathParts []string, value interface{}) error {
 	firstOfPath, err := validateAndGetFirstOfPath(pathParts)
 	if err != nil {
 		return err
 	}
 
 	if len(pathParts) == 1 {
-		yamlSetFoundValue(content, firstOfPath, value)
-	} else {
-		restOfPath := pathParts[1:]
-		_, ok := (*content)[firstOfPath].(map[interface{}]interface{})
-		if !ok {
-			logrus.Debugf("No key %s found. Creating it.\n", firstOfPath)
-			(*content)[firstOfPath] = make(map[interface{}]interface{})
+		return yamlSetFoundValue(mapping, firstOfPath, value)
+	}
+
+	restOfPath := pathParts[1:]
+	index := yamlFindKey(mapping, firstOfPath)
+	if index < 0 {
+		logrus.Debugf("No key %s found. Creating it.\n", firstOfPath)
+		mapping.Content = append(mapping.Content, yamlKeyNode(firstOfPath), &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"})
+		index = len(mapping.Content) - 2
+	}
+	subContent := mapping.Content[index+1]
+	if subContent.Kind == yaml.AliasNode {
+		return fmt.Errorf("Path goes through alias *%s, change the anchored value instead", subContent.Value)
+	}
+	if subContent.Kind != yaml.MappingNode {
+		logrus.Debugf("Key %s is not a map. Replacing it.\n", firstOfPath)
+		*subContent = yaml.Node{
+			Kind:        yaml.MappingNode,
+			Tag:         "!!map",
+			HeadComment: subContent.HeadComment,
+			LineComment: subContent.LineComment,
+			FootComment: subContent.FootComment,
 		}
-		subContent := (*content)[firstOfPath].(map[interface{}]interface{})
-		if err := yamlSetOrCreateRecursive(&subContent, restOfPath, value); err != nil {
-			return err
+	}
+
+	return yamlSetOrCreateRecursive(subContent, restOfPath, value)
+}
+
+func yamlSetFoundValue(mapping *yaml.Node, key string, value interface{}) error {
+	logrus.Debugf("Setting %s = %v\n", key, value)
+
+	var valueNode yaml.Node
+	if err := valueNode.Encode(value); err != nil {
+		return err
+	}
+
+	index := yamlFindKey(mapping, key)
+	if index < 0 {
+		mapping.Content = append(mapping.Content, yamlKeyNode(key), &valueNode)
+		return nil
+	}
+
+	existing := mapping.Content[index+1]
+	if existing.Kind == yaml.ScalarNode && valueNode.Kind == yaml.ScalarNode &&
+		existing.ShortTag() == "!!str" && valueNode.ShortTag() == "!!str" && existing.Style&yamlQuotedStyles != 0 {
+		valueNode.Style = existing.Style
+		// A | or > block keeps its single trailing newline, which would otherwise be written as |- or >-
+		if existing.Style&yamlBlockStyles != 0 && strings.HasSuffix(existing.Value, "\n") && !strings.HasSuffix(valueNode.Value, "\n") {
+			valueNode.Value += "\n"
 		}
 	}
+	if existing.Kind != yaml.AliasNode {
+		valueNode.Anchor = existing.Anchor
+	}
+	valueNode.HeadComment = existing.HeadComment
+	valueNode.LineComment = existing.LineComment
+	valueNode.FootComment = existing.FootComment
+	*existing = valueNode
 
 	return nil
 }
 
-func yamlSetFoundValue(content *map[interface{}]interface{}, key string, value string) error {
-	logrus.Debugf("Setting %s = %s\n", key, value)
-	(*content)[key] = value
+const (
+	yamlBlockStyles  = yaml.LiteralStyle | yaml.FoldedStyle
+	yamlQuotedStyles = yaml.SingleQuotedStyle | yaml.DoubleQuotedStyle | yamlBlockStyles
+)
 
-	return nil
+// yamlFindKey returns the index of the key node in a mapping node, or -1 if not found
+func yamlFindKey(mapping *yaml.Node, key string) int {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
 }
 
-func unmarshalYamlFile(auroraConfigFile *File, content *map[interface{}]interface{}) error {
-	if err := yaml.Unmarshal([]byte(auroraConfigFile.Contents), &content); err != nil {
-		return err
+func yamlKeyNode(key string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}
+}
+
+// yamlRootMapping returns the top level mapping of a document, creating it if the document is empty
+func yamlRootMapping(document *yaml.Node) *yaml.Node {
+	if document.Kind != yaml.DocumentNode {
+		*document = yaml.Node{Kind: yaml.DocumentNode, HeadComment: document.HeadComment}
+	}
+	if len(document.Content) == 0 || document.Content[0].Kind != yaml.MappingNode {
+		root := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		if len(document.Content) > 0 {
+			root.HeadComment = document.Content[0].HeadComment
+			root.FootComment = document.Content[0].FootComment
+		}
+		document.Content = []*yaml.Node{root}
 	}
+	return document.Content[0]
+}
 
-	return nil
+// yamlDetectIndent finds the indentation used for nested maps in the document
+func yamlDetectIndent(node *yaml.Node) int {
+	if node.Kind == yaml.DocumentNode || node.Kind == yaml.SequenceNode {
+		for _, child := range node.Content {
+			if indent := yamlDetectIndent(child); indent > 0 {
+				return indent
+			}
+		}
+		return 0
+	}
+	if node.Kind != yaml.MappingNode || node.Style == yaml.FlowStyle {
+		return 0
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if value.Kind == yaml.MappingNode && value.Style != yaml.FlowStyle && len(value.Content) > 0 && value.Line > key.Line {
+			if indent := value.Content[0].Column - key.Column; indent > 0 {
+				return indent
+			}
+		}
+		if indent := yamlDetectIndent(value); indent > 0 {
+			return indent
+		}
+	}
+	return 0
+}
+
+func unmarshalYamlFile(auroraConfigFile *File) (*yaml.Node, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal([]byte(auroraConfigFile.Contents), &document); err != nil {
+		return nil, err
+	}
+
+	return &document, nil
 }
 
-func marshalYamlFile(auroraConfigFile *File, content *map[interface{}]interface{}) error {
-	const yamlFileDashes = "---\n"
-	prettyfile, err := yaml.Marshal(content)
+// yamlUntagMergeKeys clears the explicit tag yaml.v3 would otherwise print in front of merge keys (<<)
+func yamlUntagMergeKeys(node *yaml.Node) {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if key := node.Content[i]; key.Kind == yaml.ScalarNode && key.Tag == "!!merge" {
+				key.Tag = ""
+			}
+		}
+	}
+	for _, child := range node.Content {
+		yamlUntagMergeKeys(child)
+	}
+}
+
+func marshalYamlFile(auroraConfigFile *File, document *yaml.Node) error {
+	prettyfile, err := renderYaml(document)
 	if err != nil {
 		return err
 	}
-	auroraConfigFile.Contents = yamlFileDashes + string(prettyfile)
+	auroraConfigFile.Contents = prettyfile
 
 	return nil
 }
+
+func renderYaml(document *yaml.Node) (string, error) {
+	yamlUntagMergeKeys(document)
+	indent := yamlDetectIndent(document)
+	if indent == 0 {
+		indent = yamlDefaultIndent
+	}
+
+	var buffer bytes.Buffer
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(indent)
+	if err := encoder.Encode(document); err != nil {
+		return "", err
+	}
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+
+	prettyfile := buffer.String()
+	if !strings.HasPrefix(prettyfile, yamlFileDashes) {
+		prettyfile = yamlFileDashes + prettyfile
+	}
+	return prettyfile, nil
+}
@@ -2,10 +2,24 @@ package auroraconfig
 
 import (
 	"github.com/stretchr/testify/assert"
-	"gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
 	"testing"
 )
 
+func yamlTestMapping(t *testing.T, content string) (*yaml.Node, *yaml.Node) {
+	var document yaml.Node
+	err := yaml.Unmarshal([]byte(content), &document)
+	assert.Nil(t, err)
+	return &document, yamlRootMapping(&document)
+}
+
+func yamlTestMarshal(t *testing.T, document *yaml.Node) string {
+	file := File{Name: "test.yaml"}
+	err := marshalYamlFile(&file, document)
+	assert.Nil(t, err)
+	return file.Contents
+}
+
 func Test_yamlRemoveEntryRecursive_Do(t *testing.T) {
 	t.Run("Should remove entry from normal YAML content", func(t *testing.T) {
 		content := `---
@@ -19,16 +33,12 @@ replicas: '1'
 version: 1.2.3
 `
 		pathParts := []string{"config", "MYAPP_KEYTOREMOVE"}
-		var yamlContent map[interface{}]interface{}
-		err := yaml.Unmarshal([]byte(content), &yamlContent)
-		assert.Nil(t, err)
+		document, yamlContent := yamlTestMapping(t, content)
 
-		err = yamlRemoveEntryRecursive(&yamlContent, pathParts)
+		err := yamlRemoveEntryRecursive(yamlContent, pathParts)
 		assert.Nil(t, err)
 
-		changedyamlbytearray, err := yaml.Marshal(yamlContent)
-		assert.Nil(t, err)
-		changedyaml := string(changedyamlbytearray)
+		changedyaml := yamlTestMarshal(t, document)
 		assert.NotNil(t, changedyaml)
 		assert.NotContains(t, changedyaml, "MYAPP_KEYTOREMOVE")
 		assert.NotContains(t, changedyaml, "sometrash")
@@ -44,11 +54,9 @@ replicas: '1'
 version: 1.2.3
 `
 		pathParts := []string{"config", "MYAPP_KEYTOREMOVE"}
-		var yamlContent map[interface{}]interface{}
-		err := yaml.Unmarshal([]byte(content), &yamlContent)
-		assert.Nil(t, err)
+		_, yamlContent := yamlTestMapping(t, content)
 
-		err = yamlRemoveEntryRecursive(&yamlContent, pathParts)
+		err := yamlRemoveEntryRecursive(yamlContent, pathParts)
 		assert.NotNil(t, err)
 		assert.Contains(t, "No such path in target YAML document", err.Error())
 	})
@@ -64,17 +72,13 @@ replicas: '1'
 version: 1.2.3
 `
 		pathParts := []string{"MYAPP_KEYTOREMOVE"}
-		var yamlContent map[interface{}]interface{}
-		err := yaml.Unmarshal([]byte(content), &yamlContent)
-		assert.Nil(t, err)
+		document, yamlContent := yamlTestMapping(t, content)
 
-		err = yamlRemoveEntryRecursive(&yamlContent, pathParts)
+		err := yamlRemoveEntryRecursive(yamlContent, pathParts)
 		assert.NotNil(t, err)
 		assert.Contains(t, "No such path in target YAML document", err.Error())
 
-		changedyamlbytearray, err := yaml.Marshal(yamlContent)
-		assert.Nil(t, err)
-		changedyaml := string(changedyamlbytearray)
+		changedyaml := yamlTestMarshal(t, document)
 		assert.NotNil(t, changedyaml)
 		assert.Contains(t, changedyaml, "MYAPP_KEYTOREMOVE")
 		assert.Contains(t, changedyaml, "sometrash")
@@ -90,23 +94,19 @@ replicas: '1'
 version: 1.2.3
 `
 		pathParts := []string{"config"}
-		var yamlContent map[interface{}]interface{}
-		err := yaml.Unmarshal([]byte(content), &yamlContent)
-		assert.Nil(t, err)
+		document, yamlContent := yamlTestMapping(t, content)
 
-		err = yamlRemoveEntryRecursive(&yamlContent, pathParts)
+		err := yamlRemoveEntryRecursive(yamlContent, pathParts)
 		assert.Nil(t, err)
 
-		changedyamlbytearray, err := yaml.Marshal(yamlContent)
-		assert.Nil(t, err)
-		changedyaml := string(changedyamlbytearray)
+		changedyaml := yamlTestMarshal(t, document)
 		assert.NotNil(t, changedyaml)
 		assert.NotContains(t, changedyaml, "config")
 		assert.NotContains(t, changedyaml, "MYAPP_SOME_KEY")
 		assert.NotContains(t, changedyaml, "somevalue")
 		assert.NotContains(t, changedyaml, "MYAPP_SOME_OTHER_KEY")
 		assert.NotContains(t, changedyaml, "someothervalue")
-		assert.Equal(t, 4, len(yamlContent))
+		assert.Equal(t, 8, len(yamlContent.Content))
 	})
 }
 
@@ -123,16 +123,12 @@ version: 1.2.3
 `
 		pathParts := []string{"config", "MYAPP_NEW_KEY"}
 		value := "newValue"
-		var yamlContent map[interface{}]interface{}
-		err := yaml.Unmarshal([]byte(content), &yamlContent)
-		assert.Nil(t, err)
+		document, yamlContent := yamlTestMapping(t, content)
 
-		err = yamlSetOrCreateRecursive(&yamlContent, pathParts, value)
+		err := yamlSetOrCreateRecursive(yamlContent, pathParts, value)
 		assert.Nil(t, err)
 
-		changedyamlbytearray, err := yaml.Marshal(yamlContent)
-		assert.Nil(t, err)
-		changedyaml := string(changedyamlbytearray)
+		changedyaml := yamlTestMarshal(t, document)
 		assert.NotNil(t, changedyaml)
 		assert.Contains(t, changedyaml, "MYAPP_NEW_KEY")
 		assert.Contains(t, changedyaml, "newValue")
@@ -140,22 +136,15 @@ version: 1.2.3
 
 	t.Run("Should set value on minimal yaml content", func(t *testing.T) {
 		content := `---`
-		expected := "MYAPP_NEW_KEY: newValue\n"
+		expected := "---\nMYAPP_NEW_KEY: newValue\n"
 		pathParts := []string{"MYAPP_NEW_KEY"}
 		value := "newValue"
-		var yamlContent map[interface{}]interface{}
-		err := yaml.Unmarshal([]byte(content), &yamlContent)
-		assert.Nil(t, err)
-		if len(yamlContent) == 0 {
-			yamlContent = make(map[interface{}]interface{})
-		}
+		document, yamlContent := yamlTestMapping(t, content)
 
-		err = yamlSetOrCreateRecursive(&yamlContent, pathParts, value)
+		err := yamlSetOrCreateRecursive(yamlContent, pathParts, value)
 		assert.Nil(t, err)
 
-		changedyamlbytearray, err := yaml.Marshal(yamlContent)
-		assert.Nil(t, err)
-		changedyaml := string(changedyamlbytearray)
+		changedyaml := yamlTestMarshal(t, document)
 		assert.NotNil(t, changedyaml)
 		assert.Contains(t, changedyaml, "MYAPP_NEW_KEY")
 		assert.Contains(t, changedyaml, "newValue")
@@ -166,19 +155,15 @@ version: 1.2.3
 		content := `---
 baseFile: myapp.yaml
 `
-		expected := "baseFile: myapp.yaml\nfirst:\n  second:\n    MYAPP_NEW_KEY: newValue\n"
+		expected := "---\nbaseFile: myapp.yaml\nfirst:\n  second:\n    MYAPP_NEW_KEY: newValue\n"
 		pathParts := []string{"first", "second", "MYAPP_NEW_KEY"}
 		value := "newValue"
-		var yamlContent map[interface{}]interface{}
-		err := yaml.Unmarshal([]byte(content), &yamlContent)
-		assert.Nil(t, err)
+		document, yamlContent := yamlTestMapping(t, content)
 
-		err = yamlSetOrCreateRecursive(&yamlContent, pathParts, value)
+		err := yamlSetOrCreateRecursive(yamlContent, pathParts, value)
 		assert.Nil(t, err)
 
-		changedyamlbytearray, err := yaml.Marshal(yamlContent)
-		assert.Nil(t, err)
-		changedyaml := string(changedyamlbytearray)
+		changedyaml := yamlTestMarshal(t, document)
 		assert.NotNil(t, changedyaml)
 		assert.Equal(t, expected, changedyaml)
 	})
@@ -189,19 +174,15 @@ baseFile: myapp.yaml
 config:
   MYAPP_SOME_KEY: somevalue
 `
-		expected := "baseFile: myapp.yaml\nconfig:\n  MYAPP_SOME_KEY: newValue\n"
+		expected := "---\nbaseFile: myapp.yaml\nconfig:\n  MYAPP_SOME_KEY: newValue\n"
 		pathParts := []string{"config", "MYAPP_SOME_KEY"}
 		value := "newValue"
-		var yamlContent map[interface{}]interface{}
-		err := yaml.Unmarshal([]byte(content), &yamlContent)
-		assert.Nil(t, err)
+		document, yamlContent := yamlTestMapping(t, content)
 
-		err = yamlSetOrCreateRecursive(&yamlContent, pathParts, value)
+		err := yamlSetOrCreateRecursive(yamlContent, pathParts, value)
 		assert.Nil(t, err)
 
-		changedyamlbytearray, err := yaml.Marshal(yamlContent)
-		assert.Nil(t, err)
-		changedyaml := string(changedyamlbytearray)
+		changedyaml := yamlTestMarshal(t, document)
 		assert.NotNil(t, changedyaml)
 		assert.Contains(t, changedyaml, "newValue")
 		assert.NotContains(t, changedyaml, "somevalue")
@@ -216,11 +197,9 @@ config:
 `
 		pathParts := []string{}
 		value := "newValue"
-		var yamlContent map[interface{}]interface{}
-		err := yaml.Unmarshal([]byte(content), &yamlContent)
-		assert.Nil(t, err)
+		_, yamlContent := yamlTestMapping(t, content)
 
-		err = yamlSetOrCreateRecursive(&yamlContent, pathParts, value)
+		err := yamlSetOrCreateRecursive(yamlContent, pathParts, value)
 		assert.NotNil(t, err)
 		assert.Equal(t, "Path can not be empty", err.Error())
 	})
@@ -233,11 +212,9 @@ config:
 `
 		pathParts := []string{"config", "270"}
 		value := "newValue"
-		var yamlContent map[interface{}]interface{}
-		err := yaml.Unmarshal([]byte(content), &yamlContent)
-		assert.Nil(t, err)
+		_, yamlContent := yamlTestMapping(t, content)
 
-		err = yamlSetOrCreateRecursive(&yamlContent, pathParts, value)
+		err := yamlSetOrCreateRecursive(yamlContent, pathParts, value)
 		assert.NotNil(t, err)
 		assert.Equal(t, "Path can not have numeric entries", err.Error())
 	})
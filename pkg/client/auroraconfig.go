@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/sirupsen/logrus"
 	"net/http"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/skatteetaten/ao/pkg/auroraconfig"
@@ -19,6 +20,7 @@ type AuroraConfigClient interface {
 	PutAuroraConfig(endpoint string, payload []byte) (string, error)
 	ValidateAuroraConfig(ac *auroraconfig.AuroraConfig, fullValidation bool) (string, error)
 	GetAuroraConfigFile(fileName string) (*auroraconfig.File, string, error)
+	SaveAuroraConfig(loaded, ac *auroraconfig.AuroraConfig) error
 }
 
 // GetAuroraConfig gets an aurora config via API calls
@@ -110,6 +112,82 @@ func (api *APIClient) ValidateRemoteAuroraConfig(fullValidation bool) (string, e
 	return api.PutAuroraConfig(endpoint, nil)
 }
 
+// SaveAuroraConfig saves the changes from loaded to ac file by file via API calls. Changed and new files are saved,
+// and files missing in ac are deleted. Nothing is saved if any of the changed or deleted files have been changed
+// remotely since loaded was fetched. The files are saved one at a time after that check, so if saving a file fails,
+// the files saved before it are kept, and the error lists them.
+func (api *APIClient) SaveAuroraConfig(loaded, ac *auroraconfig.AuroraConfig) error {
+	var changed, created []*auroraconfig.File
+	for i := range ac.Files {
+		file := &ac.Files[i]
+		if previous := loaded.GetFile(file.Name); previous == nil {
+			created = append(created, file)
+		} else if previous.Contents != file.Contents {
+			changed = append(changed, file)
+		}
+	}
+	var removed []string
+	for _, file := range loaded.Files {
+		if ac.GetFile(file.Name) == nil {
+			removed = append(removed, file.Name)
+		}
+	}
+
+	eTags := make(map[string]string)
+	checked := removed
+	for _, file := range changed {
+		checked = append(checked, file.Name)
+	}
+	for _, fileName := range checked {
+		remote, eTag, err := api.GetAuroraConfigFile(fileName)
+		if err != nil {
+			return err
+		}
+		if remote.Contents != loaded.GetFile(fileName).Contents {
+			return errors.Errorf("%s has been changed remotely since it was fetched, no files have been saved", fileName)
+		}
+		eTags[fileName] = eTag
+	}
+	if len(created) > 0 {
+		fileNames, err := api.GetFileNames()
+		if err != nil {
+			return err
+		}
+		for _, file := range created {
+			if _, err := fileNames.Find(file.Name); err == nil {
+				return errors.Errorf("%s has been created remotely since the AuroraConfig was fetched, no files have been saved", file.Name)
+			}
+		}
+	}
+
+	var saved []string
+	partialSaveError := func(err error, action, fileName string) error {
+		if len(saved) == 0 {
+			return errors.Wrapf(err, "Failed to %s %s, no files have been saved", action, fileName)
+		}
+		return errors.Wrapf(err, "Failed to %s %s, the AuroraConfig is partly saved, these changes have been saved: %s", action, fileName, strings.Join(saved, ", "))
+	}
+	for _, file := range changed {
+		if err := api.UpdateAuroraConfigFile(file, eTags[file.Name]); err != nil {
+			return partialSaveError(err, "save", file.Name)
+		}
+		saved = append(saved, "changed "+file.Name)
+	}
+	for _, file := range created {
+		if err := api.CreateAuroraConfigFile(file); err != nil {
+			return partialSaveError(err, "create", file.Name)
+		}
+		saved = append(saved, "created "+file.Name)
+	}
+	for _, fileName := range removed {
+		if err := api.DeleteAuroraConfigFile(fileName, eTags[fileName]); err != nil {
+			return partialSaveError(err, "delete", fileName)
+		}
+		saved = append(saved, "deleted "+fileName)
+	}
+	return nil
+}
+
 func formatWarnings(warnings []string) string {
 	var status string
 
@@ -41,6 +41,11 @@ func (api *AuroraConfigClientMock) ValidateAuroraConfig(ac *auroraconfig.AuroraC
 	return "", errors.New("Not implemented")
 }
 
+// SaveAuroraConfig default mock implementation
+func (api *AuroraConfigClientMock) SaveAuroraConfig(loaded, ac *auroraconfig.AuroraConfig) error {
+	return errors.New("Not implemented")
+}
+
 // GetAuroraConfigFile default mock implementation
 func (api *AuroraConfigClientMock) GetAuroraConfigFile(fileName string) (*auroraconfig.File, string, error) {
 	return nil, "", errors.New("Not implemented")
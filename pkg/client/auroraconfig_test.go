@@ -2,8 +2,10 @@ package client
 
 import (
 	"encoding/json"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/skatteetaten/ao/pkg/auroraconfig"
@@ -190,3 +192,112 @@ func TestApiClient_ValidateRemoteAuroraConfig(t *testing.T) {
 
 	})
 }
+
+func TestApiClient_SaveAuroraConfig(t *testing.T) {
+	loaded := &auroraconfig.AuroraConfig{
+		Name: affiliation,
+		Files: []auroraconfig.File{
+			{Name: "about.json", Contents: `{"affiliation":"paas"}`},
+			{Name: "utv/about.json", Contents: `{"cluster":"utv"}`},
+			{Name: "utv/foo.json", Contents: `{"version":"1"}`},
+		},
+	}
+	saveServer := func(remote map[string]string, requests *[]string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			fileName := strings.TrimPrefix(req.URL.Path, "/v1/auroraconfig/"+affiliation+"/")
+			switch {
+			case req.Method == http.MethodGet:
+				w.Header().Set("ETag", "etag-"+fileName)
+				response, _ := json.Marshal(map[string]interface{}{
+					"success": true,
+					"items":   []auroraconfig.File{{Name: fileName, Contents: remote[fileName]}},
+				})
+				w.Write(response)
+			case strings.HasSuffix(req.URL.Path, "/graphql"):
+				body, _ := ioutil.ReadAll(req.Body)
+				switch {
+				case strings.Contains(string(body), "updateAuroraConfigFile"):
+					*requests = append(*requests, "update "+string(body))
+					w.Write(ReadTestFile("updateauroraconfigfile_success_response"))
+				case strings.Contains(string(body), "createAuroraConfigFile"):
+					*requests = append(*requests, "create "+string(body))
+					w.Write(ReadTestFile("createauroraconfigfile_success_response"))
+				case strings.Contains(string(body), "deleteAuroraConfigFile"):
+					*requests = append(*requests, "delete "+string(body))
+					if strings.Contains(string(body), "utv/fail.json") {
+						w.Write([]byte(`{"data":{"deleteAuroraConfigFile":{"message":"File is locked","success":false}}}`))
+					} else {
+						w.Write([]byte(`{"data":{"deleteAuroraConfigFile":{"message":"File successfully deleted","success":true}}}`))
+					}
+				default:
+					w.Write([]byte(`{"data":{"auroraConfig":{"files":[{"name":"about.json"},{"name":"utv/about.json"},{"name":"utv/foo.json"}]}}}`))
+				}
+			}
+		}))
+	}
+
+	t.Run("Should save changed and new files and delete removed files with their ETags", func(t *testing.T) {
+		remote := map[string]string{}
+		for _, file := range loaded.Files {
+			remote[file.Name] = file.Contents
+		}
+		var requests []string
+		ts := saveServer(remote, &requests)
+		defer ts.Close()
+
+		ac := loaded.Copy()
+		ac.Files[1].Contents = `{"cluster":"test"}`
+		ac.Files = append(ac.Files[:2], auroraconfig.File{Name: "utv/bar.json", Contents: `{"version":"2"}`})
+
+		api := NewAPIClientDefaultRef(ts.URL, ts.URL, "", affiliation, "")
+		err := api.SaveAuroraConfig(loaded, ac)
+		assert.NoError(t, err)
+
+		assert.Len(t, requests, 3)
+		assert.Contains(t, requests[0], "update ")
+		assert.Contains(t, requests[0], `"existingHash":"etag-utv/about.json"`)
+		assert.Contains(t, requests[1], "create ")
+		assert.Contains(t, requests[1], `"fileName":"utv/bar.json"`)
+		assert.Contains(t, requests[2], "delete ")
+		assert.Contains(t, requests[2], `"fileName":"utv/foo.json"`)
+		assert.Contains(t, requests[2], `"existingHash":"etag-utv/foo.json"`)
+		assert.Len(t, loaded.Files, 3)
+	})
+
+	t.Run("Should not save anything when a file has been changed remotely", func(t *testing.T) {
+		remote := map[string]string{"utv/foo.json": `{"version":"3"}`}
+		var requests []string
+		ts := saveServer(remote, &requests)
+		defer ts.Close()
+
+		ac := loaded.Copy()
+		ac.Files[0].Contents = `{"affiliation":"paas","changed":true}`
+		ac.Files = ac.Files[:2]
+
+		api := NewAPIClientDefaultRef(ts.URL, ts.URL, "", affiliation, "")
+		err := api.SaveAuroraConfig(loaded, ac)
+		assert.EqualError(t, err, "utv/foo.json has been changed remotely since it was fetched, no files have been saved")
+		assert.Empty(t, requests)
+	})
+
+	t.Run("Should list the saved changes when saving a file fails", func(t *testing.T) {
+		failing := loaded.Copy()
+		failing.Files = append(failing.Files, auroraconfig.File{Name: "utv/fail.json", Contents: `{"version":"4"}`})
+		remote := map[string]string{}
+		for _, file := range failing.Files {
+			remote[file.Name] = file.Contents
+		}
+		var requests []string
+		ts := saveServer(remote, &requests)
+		defer ts.Close()
+
+		ac := failing.Copy()
+		ac.Files[0].Contents = `{"affiliation":"paas","changed":true}`
+		ac.Files = ac.Files[:3]
+
+		api := NewAPIClientDefaultRef(ts.URL, ts.URL, "", affiliation, "")
+		err := api.SaveAuroraConfig(failing, ac)
+		assert.EqualError(t, err, "Failed to delete utv/fail.json, the AuroraConfig is partly saved, these changes have been saved: changed about.json: Remote error: File is locked\n")
+	})
+}
@@ -112,6 +112,50 @@ func (api *APIClient) UpdateAuroraConfigFile(file *auroraconfig.File, eTag strin
 	return nil
 }
 
+const deleteAuroraConfigFileRequestString = `mutation deleteAuroraConfigFile($deleteAuroraConfigFileInput: DeleteAuroraConfigFileInput!){
+  deleteAuroraConfigFile(input: $deleteAuroraConfigFileInput)
+  {
+    message
+    success
+  }
+}`
+
+// DeleteAuroraConfigFileInput is input to the graphql deleteAuroraConfigFile interface
+type DeleteAuroraConfigFileInput struct {
+	AuroraConfigName      string `json:"auroraConfigName"`
+	AuroraConfigReference string `json:"auroraConfigReference"`
+	FileName              string `json:"fileName"`
+	ExistingHash          string `json:"existingHash"`
+}
+
+// DeleteAuroraConfigFileResponse is response from the named graphql mutation "deleteAuroraConfigFile"
+type DeleteAuroraConfigFileResponse struct {
+	DeleteAuroraConfigFile AuroraConfigFileValidationResponse `json:"deleteAuroraConfigFile"`
+}
+
+// DeleteAuroraConfigFile deletes an Aurora config file via API call (graphql). The file is only deleted if its ETag matches eTag
+func (api *APIClient) DeleteAuroraConfigFile(fileName string, eTag string) error {
+	logrus.Debugf("DeleteAuroraConfigFile: ETag: %s", eTag)
+	deleteAuroraConfigFileRequest := graphql.NewRequest(deleteAuroraConfigFileRequestString)
+	deleteAuroraConfigFileInput := DeleteAuroraConfigFileInput{
+		AuroraConfigName:      api.Affiliation,
+		AuroraConfigReference: api.RefName,
+		FileName:              fileName,
+		ExistingHash:          eTag,
+	}
+	deleteAuroraConfigFileRequest.Var("deleteAuroraConfigFileInput", deleteAuroraConfigFileInput)
+
+	var deleteAuroraConfigFileResponse DeleteAuroraConfigFileResponse
+	if err := api.RunGraphQlMutation(deleteAuroraConfigFileRequest, &deleteAuroraConfigFileResponse); err != nil {
+		return err
+	}
+	if !deleteAuroraConfigFileResponse.DeleteAuroraConfigFile.Success {
+		return errors.Errorf("Remote error: %s\n", deleteAuroraConfigFileResponse.DeleteAuroraConfigFile.Message)
+	}
+
+	return nil
+}
+
 type FileNamesResponse struct {
 	AuroraConfig struct {
 		Files []struct {
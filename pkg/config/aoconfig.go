@@ -41,6 +41,8 @@ type AOConfig struct {
 	PreferredAPIClusters    []string `json:"preferredApiClusters"`
 	AvailableUpdateClusters []string `json:"availableUpdateClusters"`
 
+	Groups map[string][]string `json:"groups,omitempty"` // Named groups of applications, see `ao group`
+
 	FileAOVersion string `json:"aoVersion"` // For detecting possible changes to saved file
 }
 
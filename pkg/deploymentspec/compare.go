@@ -0,0 +1,80 @@
+package deploymentspec
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ExpectedDifferences are fields that normally differ between environments
+var ExpectedDifferences = []string{"applicationDeploymentRef", "cluster", "envName", "env", "namespace"}
+
+// Difference is a field with different values in two deployment specs. Value is nil when the field is not set.
+type Difference struct {
+	Path  string      `json:"path"`
+	Left  interface{} `json:"left"`
+	Right interface{} `json:"right"`
+}
+
+// Values returns the value of every field in the deployment spec by path
+func (spec DeploymentSpec) Values() (map[string]interface{}, error) {
+	provenances, err := spec.Explain("/")
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]interface{})
+	for _, provenance := range provenances {
+		values[provenance.Path] = provenance.Value
+	}
+	return values, nil
+}
+
+// Compare returns the fields with different values in two deployment specs, ordered by path.
+// Values are compared by their text representation, so 1 and "1" are equal.
+// Fields below any of the paths in ignore are skipped.
+func Compare(left, right DeploymentSpec, ignore []string) ([]Difference, error) {
+	leftValues, err := left.Values()
+	if err != nil {
+		return nil, err
+	}
+	rightValues, err := right.Values()
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make(map[string]bool)
+	for path := range leftValues {
+		paths[path] = true
+	}
+	for path := range rightValues {
+		paths[path] = true
+	}
+
+	var differences []Difference
+	for path := range paths {
+		if isIgnored(path, ignore) {
+			continue
+		}
+		leftValue, leftExists := leftValues[path]
+		rightValue, rightExists := rightValues[path]
+		if leftExists == rightExists && fmt.Sprint(leftValue) == fmt.Sprint(rightValue) {
+			continue
+		}
+		differences = append(differences, Difference{Path: path, Left: leftValue, Right: rightValue})
+	}
+
+	sort.Slice(differences, func(i, j int) bool {
+		return differences[i].Path < differences[j].Path
+	})
+	return differences, nil
+}
+
+func isIgnored(path string, ignore []string) bool {
+	for _, ignored := range ignore {
+		ignored = "/" + strings.Trim(ignored, "/")
+		if path == ignored || strings.HasPrefix(path, ignored+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package deploymentspec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CompareEqualSpecs(t *testing.T) {
+	differences, err := Compare(*readTestFile(t), *readTestFile(t), nil)
+	assert.NoError(t, err)
+	assert.Empty(t, differences)
+}
+
+func Test_CompareDifferentSpecs(t *testing.T) {
+	left := NewDeploymentSpec("foo", "test", "utv", "1.0")
+	right := NewDeploymentSpec("foo", "prod", "prod", "1.1")
+	right["replicas"] = map[string]interface{}{"value": 2, "source": "prod/foo.json"}
+	left["replicas"] = map[string]interface{}{"value": "2", "source": "test/foo.json"}
+
+	differences, err := Compare(withSources(left), withSources(right), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []Difference{
+		{Path: "/applicationDeploymentRef", Left: "test/foo", Right: "prod/foo"},
+		{Path: "/cluster", Left: "utv", Right: "prod"},
+		{Path: "/envName", Left: "test", Right: "prod"},
+		{Path: "/version", Left: "1.0", Right: "1.1"},
+	}, differences)
+
+	differences, err = Compare(withSources(left), withSources(right), ExpectedDifferences)
+	assert.NoError(t, err)
+	assert.Equal(t, []Difference{
+		{Path: "/version", Left: "1.0", Right: "1.1"},
+	}, differences)
+}
+
+func Test_CompareMissingFields(t *testing.T) {
+	left := withSources(NewDeploymentSpec("foo", "test", "utv", "1.0"))
+	right := withSources(NewDeploymentSpec("foo", "test", "utv", "1.0"))
+	right["route"] = map[string]interface{}{
+		"source": "default",
+		"value":  true,
+		"foo":    map[string]interface{}{"host": map[string]interface{}{"source": "foo.json", "value": "foo"}},
+	}
+
+	differences, err := Compare(left, right, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []Difference{
+		{Path: "/route", Left: nil, Right: true},
+		{Path: "/route/foo/host", Left: nil, Right: "foo"},
+	}, differences)
+
+	differences, err = Compare(left, right, []string{"route"})
+	assert.NoError(t, err)
+	assert.Empty(t, differences)
+}
+
+func Test_CompareMalformedSpec(t *testing.T) {
+	left := withSources(NewDeploymentSpec("foo", "test", "utv", "1.0"))
+	right := withSources(NewDeploymentSpec("foo", "test", "utv", "1.0"))
+	right["replicas"] = map[string]interface{}{"value": 2, "source": "foo.json", "sources": "foo.json"}
+
+	_, err := Compare(left, right, nil)
+	assert.Error(t, err)
+}
+
+func withSources(spec DeploymentSpec) DeploymentSpec {
+	for _, field := range spec {
+		field.(map[string]interface{})["source"] = "static"
+	}
+	return spec
+}
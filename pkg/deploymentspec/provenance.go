@@ -0,0 +1,115 @@
+package deploymentspec
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type (
+	// Provenance describes where a value in a deployment spec comes from
+	Provenance struct {
+		Path    string      `json:"path"`
+		Value   interface{} `json:"value"`
+		Source  string      `json:"source"`
+		Sources []Source    `json:"sources"`
+	}
+
+	// Source is a file, default or static rule setting a value in a deployment spec
+	Source struct {
+		Name  string      `json:"name"`
+		Value interface{} `json:"value"`
+	}
+)
+
+// Overridden returns the sources whose values were overridden by the effective source, highest precedence first
+func (p Provenance) Overridden() []Source {
+	winner := -1
+	for i := len(p.Sources) - 1; i >= 0; i-- {
+		if p.Sources[i].Name == p.Source {
+			winner = i
+			break
+		}
+	}
+
+	var overridden []Source
+	for i := len(p.Sources) - 1; i >= 0; i-- {
+		if i != winner {
+			overridden = append(overridden, p.Sources[i])
+		}
+	}
+	return overridden
+}
+
+// Explain returns the provenance of the field at jsonPointer, or of every field below it if it is an object
+func (spec DeploymentSpec) Explain(jsonPointer string) ([]Provenance, error) {
+	pointers := strings.Fields(strings.Replace(jsonPointer, "/", " ", -1))
+	current := map[string]interface{}(spec)
+	for _, pointer := range pointers {
+		next, ok := current[pointer].(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("%s is not set in the deployment spec", jsonPointer)
+		}
+		current = next
+	}
+
+	return explainNode("/"+strings.Join(pointers, "/"), current)
+}
+
+func explainNode(path string, node map[string]interface{}) ([]Provenance, error) {
+	var provenances []Provenance
+	if isProvenance(node) {
+		source, _ := node["source"].(string)
+		var sources []Source
+		data, err := json.Marshal(node["sources"])
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &sources); err != nil {
+			return nil, errors.Wrapf(err, "could not read sources of %s", path)
+		}
+		provenances = append(provenances, Provenance{
+			Path:    path,
+			Value:   node["value"],
+			Source:  source,
+			Sources: sources,
+		})
+	}
+
+	var keys []string
+	for key, value := range node {
+		if _, ok := value.(map[string]interface{}); ok && !(isProvenance(node) && isSourceKey(key)) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		children, err := explainNode(strings.TrimSuffix(path, "/")+"/"+key, node[key].(map[string]interface{}))
+		if err != nil {
+			return nil, err
+		}
+		provenances = append(provenances, children...)
+	}
+	return provenances, nil
+}
+
+// isSourceKey checks if a key of a node with provenance holds the value or sources of the field, rather than a nested field
+func isSourceKey(key string) bool {
+	return key == "value" || key == "source" || key == "sources"
+}
+
+// isProvenance checks if a node holds the value and sources of a field, rather than only nested fields
+func isProvenance(node map[string]interface{}) bool {
+	if _, ok := node["value"]; !ok {
+		return false
+	}
+	_, hasSource := node["source"].(string)
+	sources, hasSources := node["sources"]
+	if _, isField := sources.(map[string]interface{}); isField {
+		hasSources = false
+	}
+	return hasSource || hasSources
+}
@@ -0,0 +1,90 @@
+package deploymentspec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ExplainField(t *testing.T) {
+	provenances, err := readTestFile(t).Explain("/pause")
+	assert.NoError(t, err)
+	assert.Len(t, provenances, 1)
+
+	pause := provenances[0]
+	assert.Equal(t, "/pause", pause.Path)
+	assert.Equal(t, "false", pause.Value)
+	assert.Equal(t, "dev/flubber.yaml", pause.Source)
+	assert.Equal(t, []Source{{Name: "default", Value: false}}, pause.Overridden())
+}
+
+func Test_ExplainSubtree(t *testing.T) {
+	provenances, err := readTestFile(t).Explain("resources/cpu")
+	assert.NoError(t, err)
+
+	var paths []string
+	for _, provenance := range provenances {
+		paths = append(paths, provenance.Path)
+	}
+	assert.Equal(t, []string{"/resources/cpu/max", "/resources/cpu/min"}, paths)
+	assert.Equal(t, "200m", provenances[0].Value)
+	assert.Equal(t, []Source{{Name: "default", Value: "2000m"}}, provenances[0].Overridden())
+}
+
+func Test_ExplainFieldWithValueAndSubfields(t *testing.T) {
+	provenances, err := readTestFile(t).Explain("/management")
+	assert.NoError(t, err)
+
+	var paths []string
+	for _, provenance := range provenances {
+		paths = append(paths, provenance.Path)
+	}
+	assert.Equal(t, []string{"/management", "/management/path", "/management/port"}, paths)
+}
+
+func Test_ExplainFieldsNamedLikeSources(t *testing.T) {
+	spec := DeploymentSpec{
+		"config": map[string]interface{}{
+			"source": map[string]interface{}{"value": "git", "source": "utv/foo.json", "sources": []interface{}{}},
+			"value":  map[string]interface{}{"value": "1", "source": "foo.json"},
+		},
+		"secrets": map[string]interface{}{
+			"value":   map[string]interface{}{"value": "a", "source": "foo.json"},
+			"sources": map[string]interface{}{"value": "b", "source": "foo.json"},
+		},
+	}
+
+	provenances, err := spec.Explain("/config")
+	assert.NoError(t, err)
+
+	var paths []string
+	for _, provenance := range provenances {
+		paths = append(paths, provenance.Path)
+	}
+	assert.Equal(t, []string{"/config/source", "/config/value"}, paths)
+	assert.Equal(t, "git", provenances[0].Value)
+
+	provenances, err = spec.Explain("/config/value")
+	assert.NoError(t, err)
+	assert.Equal(t, "foo.json", provenances[0].Source)
+
+	provenances, err = spec.Explain("/secrets")
+	assert.NoError(t, err)
+	assert.Len(t, provenances, 2)
+}
+
+func Test_ExplainNonExistingField(t *testing.T) {
+	_, err := readTestFile(t).Explain("/does/not/exist")
+	assert.EqualError(t, err, "/does/not/exist is not set in the deployment spec")
+}
+
+func Test_OverriddenKeepsEarlierSourcesWithSameName(t *testing.T) {
+	provenance := Provenance{
+		Source: "flubber.json",
+		Sources: []Source{
+			{Name: "fileName", Value: "flubber"},
+			{Name: "flubber.json", Value: "flubber"},
+		},
+	}
+	assert.Equal(t, []Source{{Name: "fileName", Value: "flubber"}}, provenance.Overridden())
+}
@@ -0,0 +1,141 @@
+package deploymentspec
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Selector operators
+const (
+	OperatorEquals     = "="
+	OperatorNotEquals  = "!="
+	OperatorMatches    = "~"
+	OperatorNotMatches = "!~"
+)
+
+const (
+	selectorSeparator     = ","
+	selectorOperatorChars = "!=~"
+)
+
+// Requirement is a condition on the value of a field in a deployment spec
+type Requirement struct {
+	Field    string
+	Operator string
+	Value    string
+	pattern  *regexp.Regexp
+}
+
+// Selector selects deployment specs matching all of its requirements
+type Selector []Requirement
+
+// ParseSelector parses a comma separated list of requirements such as "cluster=utv04,version!~SNAPSHOT".
+// The operators are = and != for equality, and ~ and !~ for regular expressions. Fields in nested
+// objects are given as paths, such as deployStrategy/type. An empty expression selects all deployment specs.
+// Commas in a regular expression separate requirements only outside of (), [] and {}, so name~a{1,3} is
+// a single requirement. Use \, for other commas in regular expressions.
+func ParseSelector(expression string) (Selector, error) {
+	var selector Selector
+	if strings.TrimSpace(expression) == "" {
+		return selector, nil
+	}
+
+	for _, part := range splitSelector(expression) {
+		index := strings.IndexAny(part, selectorOperatorChars)
+		if index < 0 {
+			return nil, errors.Errorf("%s has no operator, use one of =, !=, ~ or !~", part)
+		}
+		operator := part[index : index+1]
+		if strings.HasPrefix(part[index:], OperatorNotEquals) || strings.HasPrefix(part[index:], OperatorNotMatches) {
+			operator = part[index : index+2]
+		} else if operator == "!" {
+			return nil, errors.Errorf("%s has an unknown operator, use one of =, !=, ~ or !~", part)
+		}
+
+		requirement := Requirement{
+			Field:    strings.TrimSpace(part[:index]),
+			Operator: operator,
+			Value:    strings.TrimSpace(part[index+len(operator):]),
+		}
+		if requirement.Field == "" {
+			return nil, errors.Errorf("%s has no field", part)
+		}
+		if operator == OperatorMatches || operator == OperatorNotMatches {
+			pattern, err := regexp.Compile(requirement.Value)
+			if err != nil {
+				return nil, errors.Wrapf(err, "%s has an invalid regular expression", part)
+			}
+			requirement.pattern = pattern
+		}
+		selector = append(selector, requirement)
+	}
+	return selector, nil
+}
+
+// splitSelector splits an expression into requirements at the commas that are not part of a regular expression
+func splitSelector(expression string) []string {
+	var parts []string
+	start, depth := 0, 0
+	isRegex, hasOperator, inClass := false, false, false
+	for i := 0; i < len(expression); i++ {
+		c := expression[i]
+		switch {
+		case !hasOperator && strings.IndexByte(selectorOperatorChars, c) >= 0:
+			hasOperator = true
+			isRegex = strings.HasPrefix(expression[i:], OperatorMatches) || strings.HasPrefix(expression[i:], OperatorNotMatches)
+		case isRegex && c == '\\':
+			i++
+		case isRegex && inClass:
+			inClass = c != ']'
+		case isRegex && c == '[':
+			inClass = true
+		case isRegex && (c == '(' || c == '{'):
+			depth++
+		case isRegex && (c == ')' || c == '}') && depth > 0:
+			depth--
+		case c == selectorSeparator[0] && depth == 0:
+			parts = append(parts, expression[start:i])
+			start, isRegex, hasOperator = i+1, false, false
+		}
+	}
+	return append(parts, expression[start:])
+}
+
+// Matches returns true if the value of the field in spec satisfies the requirement. A field that is not set has the value "-".
+func (r Requirement) Matches(spec DeploymentSpec) bool {
+	value := spec.GetString(r.Field)
+	switch r.Operator {
+	case OperatorEquals:
+		return value == r.Value
+	case OperatorNotEquals:
+		return value != r.Value
+	case OperatorMatches:
+		return r.pattern.MatchString(value)
+	case OperatorNotMatches:
+		return !r.pattern.MatchString(value)
+	}
+	return false
+}
+
+// Matches returns true if spec satisfies all requirements of the selector
+func (s Selector) Matches(spec DeploymentSpec) bool {
+	for _, requirement := range s {
+		if !requirement.Matches(spec) {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter returns the deployment specs matching the selector
+func (s Selector) Filter(specs []DeploymentSpec) []DeploymentSpec {
+	var selected []DeploymentSpec
+	for _, spec := range specs {
+		if s.Matches(spec) {
+			selected = append(selected, spec)
+		}
+	}
+	return selected
+}
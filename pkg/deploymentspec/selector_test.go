@@ -0,0 +1,75 @@
+package deploymentspec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSelector(t *testing.T) {
+	selector, err := ParseSelector("cluster=utv04, type != deploy,version!~SNAPSHOT,name~^foo")
+	assert.NoError(t, err)
+	assert.Len(t, selector, 4)
+	assert.Equal(t, "cluster", selector[0].Field)
+	assert.Equal(t, OperatorEquals, selector[0].Operator)
+	assert.Equal(t, "utv04", selector[0].Value)
+	assert.Equal(t, "type", selector[1].Field)
+	assert.Equal(t, OperatorNotEquals, selector[1].Operator)
+	assert.Equal(t, "deploy", selector[1].Value)
+	assert.Equal(t, OperatorNotMatches, selector[2].Operator)
+	assert.Equal(t, OperatorMatches, selector[3].Operator)
+
+	selector, err = ParseSelector("")
+	assert.NoError(t, err)
+	assert.Empty(t, selector)
+
+	_, err = ParseSelector("cluster")
+	assert.EqualError(t, err, "cluster has no operator, use one of =, !=, ~ or !~")
+
+	_, err = ParseSelector("cluster!utv04")
+	assert.EqualError(t, err, "cluster!utv04 has an unknown operator, use one of =, !=, ~ or !~")
+
+	_, err = ParseSelector("=utv04")
+	assert.EqualError(t, err, "=utv04 has no field")
+
+	_, err = ParseSelector("version~(")
+	assert.Error(t, err)
+}
+
+func TestParseSelectorWithCommasInRegularExpressions(t *testing.T) {
+	selector, err := ParseSelector(`name~^a{1,3}$,cluster=utv04,version!~(1,2|[,]),type~a\,b`)
+	assert.NoError(t, err)
+
+	var values []string
+	for _, requirement := range selector {
+		values = append(values, requirement.Value)
+	}
+	assert.Equal(t, []string{"^a{1,3}$", "utv04", "(1,2|[,])", `a\,b`}, values)
+	assert.True(t, selector[0].pattern.MatchString("aaa"))
+	assert.False(t, selector[0].pattern.MatchString("aaaa"))
+	assert.True(t, selector[3].pattern.MatchString("a,b"))
+
+	_, err = ParseSelector("route=a{1,2}")
+	assert.EqualError(t, err, "2} has no operator, use one of =, !=, ~ or !~")
+}
+
+func TestSelectorFilter(t *testing.T) {
+	specs := []DeploymentSpec{
+		NewDeploymentSpec("foo", "utv", "utv04", "1.0.0"),
+		NewDeploymentSpec("bar", "utv", "utv04", "1.0.0-SNAPSHOT"),
+		NewDeploymentSpec("baz", "test", "utv05", "2.0.0"),
+	}
+	specs[0]["deployStrategy"] = map[string]interface{}{"type": map[string]interface{}{"value": "rolling"}}
+
+	selector, err := ParseSelector("cluster=utv04,version!~SNAPSHOT")
+	assert.NoError(t, err)
+	assert.Equal(t, []DeploymentSpec{specs[0]}, selector.Filter(specs))
+
+	selector, err = ParseSelector("deployStrategy/type!=rolling")
+	assert.NoError(t, err)
+	assert.Equal(t, []DeploymentSpec{specs[1], specs[2]}, selector.Filter(specs))
+
+	selector, err = ParseSelector("")
+	assert.NoError(t, err)
+	assert.Equal(t, specs, selector.Filter(specs))
+}
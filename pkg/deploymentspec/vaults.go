@@ -0,0 +1,71 @@
+package deploymentspec
+
+import (
+	"fmt"
+	"sort"
+)
+
+// DefaultVaultFile is the secret read from a vault in secretVaults when no file is given
+const DefaultVaultFile = "latest.properties"
+
+// VaultReference is a vault used by an application, with the secret and keys read from it
+type VaultReference struct {
+	// Field is the field of the deployment spec referring to the vault
+	Field string
+	Vault string
+	// File is the secret the keys are read from, or empty if they can be in any properties secret
+	File string
+	Keys []string
+}
+
+// VaultReferences returns the vaults referred to by secretVault and the enabled secretVaults
+func (spec DeploymentSpec) VaultReferences() []VaultReference {
+	var references []VaultReference
+	if spec.HasValue("secretVault") {
+		references = append(references, VaultReference{Field: "secretVault", Vault: spec.GetString("secretVault")})
+	} else if spec.HasValue("secretVault/name") {
+		references = append(references, VaultReference{
+			Field: "secretVault",
+			Vault: spec.GetString("secretVault/name"),
+			Keys:  spec.getStrings("secretVault/keys"),
+		})
+	}
+
+	secretVaults, ok := spec["secretVaults"].(map[string]interface{})
+	if !ok {
+		return references
+	}
+	var names []string
+	for name := range secretVaults {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		field := "secretVaults/" + name
+		if spec.HasValue(field+"/enabled") && !spec.GetBool(field+"/enabled") {
+			continue
+		}
+		reference := VaultReference{Field: field, Vault: name, File: DefaultVaultFile, Keys: spec.getStrings(field + "/keys")}
+		if spec.HasValue(field + "/name") {
+			reference.Vault = spec.GetString(field + "/name")
+		}
+		if spec.HasValue(field + "/file") {
+			reference.File = spec.GetString(field + "/file")
+		}
+		references = append(references, reference)
+	}
+	return references
+}
+
+func (spec DeploymentSpec) getStrings(name string) []string {
+	values, ok := spec.Get(name).([]interface{})
+	if !ok {
+		return nil
+	}
+	var result []string
+	for _, value := range values {
+		result = append(result, fmt.Sprintf("%v", value))
+	}
+	return result
+}
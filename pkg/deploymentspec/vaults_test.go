@@ -0,0 +1,43 @@
+package deploymentspec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func value(v interface{}) map[string]interface{} {
+	return map[string]interface{}{"value": v, "source": "about.json"}
+}
+
+func TestDeploymentSpec_VaultReferences(t *testing.T) {
+	spec := NewDeploymentSpec("foo", "dev", "utv", "1")
+	assert.Empty(t, spec.VaultReferences())
+
+	spec["secretVault"] = value("legacy")
+	spec["secretVaults"] = map[string]interface{}{
+		"db": map[string]interface{}{
+			"keys": value([]interface{}{"db.user", "db.password"}),
+		},
+		"api": map[string]interface{}{
+			"name": value("external-api"),
+			"file": value("api.properties"),
+		},
+		"off": map[string]interface{}{
+			"enabled": value(false),
+		},
+	}
+
+	assert.Equal(t, []VaultReference{
+		{Field: "secretVault", Vault: "legacy"},
+		{Field: "secretVaults/api", Vault: "external-api", File: "api.properties"},
+		{Field: "secretVaults/db", Vault: "db", File: DefaultVaultFile, Keys: []string{"db.user", "db.password"}},
+	}, spec.VaultReferences())
+
+	spec = NewDeploymentSpec("foo", "dev", "utv", "1")
+	spec["secretVault"] = map[string]interface{}{
+		"name": value("legacy"),
+		"keys": value([]interface{}{"password"}),
+	}
+	assert.Equal(t, []VaultReference{{Field: "secretVault", Vault: "legacy", Keys: []string{"password"}}}, spec.VaultReferences())
+}
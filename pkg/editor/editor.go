@@ -18,6 +18,9 @@ import (
 const (
 	cancelMessage = "Edit cancelled, no changes made."
 
+	// FileHeader starts the line naming the file below it when several files are edited in one buffer
+	FileHeader = "## File: "
+
 	editPattern = `## Name: %s
 ## Please edit the object below. Lines beginning with '##' will be ignored,
 ## and an empty file will abort the edit. If an error occurs while saving this file will be
@@ -30,6 +33,19 @@ type (
 	// OnSaveFunc is called on save from editor
 	OnSaveFunc func(modifiedContent string) error
 
+	// ReplaceContentError is returned from OnSave to reopen the editor with other content than was saved,
+	// such as the result of a merge with conflict markers
+	ReplaceContentError struct {
+		Message string
+		Content string
+	}
+
+	// File is a named file edited together with other files
+	File struct {
+		Name    string
+		Content string
+	}
+
 	// Editor specifies editor functions
 	Editor struct {
 		OpenEditor func(string) error
@@ -47,6 +63,16 @@ func NewEditor(saveFunc OnSaveFunc) *Editor {
 
 // Edit updates content during editing
 func (e Editor) Edit(content string, name string) error {
+	return e.edit(content, name, stripComments)
+}
+
+// EditFiles edits several files in one buffer, where each file is preceded by a FileHeader line.
+// OnSave is called with the whole buffer, use SplitFiles to get the files.
+func (e Editor) EditFiles(files []File) error {
+	return e.edit(JoinFiles(files), fmt.Sprintf("%d files", len(files)), stripCommentsKeepingFileHeaders)
+}
+
+func (e Editor) edit(content string, name string, strip func(string) string) error {
 
 	tempFilePath, err := createTempFile()
 	if err != nil {
@@ -85,7 +111,7 @@ func (e Editor) Edit(content string, name string) error {
 			return err
 		}
 
-		currentContent = stripComments(string(fileContent))
+		currentContent = strip(string(fileContent))
 		if previousContent == currentContent {
 			return errors.New(cancelMessage)
 		}
@@ -93,6 +119,10 @@ func (e Editor) Edit(content string, name string) error {
 		err = e.OnSave(currentContent)
 		if err != nil {
 			editErrors = addErrorMessage(err.Error())
+			var replaceContent *ReplaceContentError
+			if errors.As(err, &replaceContent) {
+				currentContent = replaceContent.Content
+			}
 		} else {
 			done = true
 		}
@@ -101,6 +131,10 @@ func (e Editor) Edit(content string, name string) error {
 	return nil
 }
 
+func (e *ReplaceContentError) Error() string {
+	return e.Message
+}
+
 func openEditor(filename string) error {
 	var editor = os.Getenv("EDITOR")
 	if editor == "" {
@@ -168,6 +202,53 @@ func stripComments(content string) string {
 	return actualContent
 }
 
+func stripCommentsKeepingFileHeaders(content string) string {
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if strings.HasPrefix(strings.TrimSpace(line), FileHeader) {
+			lines = append(lines, strings.TrimSpace(line))
+		} else if stripped := stripComments(line); stripped != "" || strings.TrimSpace(line) == "" {
+			lines = append(lines, stripped)
+		}
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
+
+// JoinFiles joins files into one buffer, each preceded by a FileHeader line
+func JoinFiles(files []File) string {
+	var sections []string
+	for _, file := range files {
+		sections = append(sections, FileHeader+file.Name+"\n"+strings.TrimRight(file.Content, "\n"))
+	}
+	return strings.Join(sections, "\n\n")
+}
+
+// SplitFiles splits a buffer joined by JoinFiles into its files
+func SplitFiles(content string) ([]File, error) {
+	var files []File
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, FileHeader) {
+			if len(files) > 0 {
+				files[len(files)-1].Content = strings.TrimRight(strings.Join(lines, "\n"), "\n")
+			}
+			files = append(files, File{Name: strings.TrimSpace(strings.TrimPrefix(line, FileHeader))})
+			lines = nil
+			continue
+		}
+		if len(files) == 0 && strings.TrimSpace(line) != "" {
+			return nil, errors.Errorf("Content must start with a line like '%s<name>'", FileHeader)
+		}
+		lines = append(lines, line)
+	}
+	if len(files) == 0 {
+		return nil, errors.New("No files to save")
+	}
+	files[len(files)-1].Content = strings.TrimRight(strings.Join(lines, "\n"), "\n")
+	return files, nil
+}
+
 func addErrorMessage(errorMessage string) string {
 	comments := "##\n## ERROR:\n"
 	for _, line := range strings.Split(errorMessage, "\n") {
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"strings"
 	"testing"
 
 	"github.com/pkg/errors"
@@ -106,3 +107,69 @@ func TestStripComments(t *testing.T) {
 	noComments := stripComments(content)
 	assert.Equal(t, "{}", noComments)
 }
+
+func TestEditor_EditReplaceContent(t *testing.T) {
+	var opened []string
+	fileEditor := NewEditor(func(modifiedContent string) error {
+		if modifiedContent == "merged" {
+			return nil
+		}
+		return &ReplaceContentError{Message: "conflict", Content: "conflicting"}
+	})
+	fileEditor.OpenEditor = func(tempFile string) error {
+		data, err := ioutil.ReadFile(tempFile)
+		if err != nil {
+			t.Error(err)
+		}
+		opened = append(opened, stripComments(string(data)))
+		edit := "mine"
+		if len(opened) == 2 {
+			edit = "merged"
+		}
+		return ioutil.WriteFile(tempFile, []byte(edit), 0700)
+	}
+
+	err := fileEditor.Edit("original", "foo.json")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"original", "conflicting"}, opened)
+}
+
+func TestJoinAndSplitFiles(t *testing.T) {
+	files := []File{
+		{Name: "about.json", Content: "{\n  \"cluster\": \"utv\"\n}\n"},
+		{Name: "utv/foo.yaml", Content: "version: \"1\""},
+	}
+	joined := JoinFiles(files)
+	assert.Equal(t, "## File: about.json\n{\n  \"cluster\": \"utv\"\n}\n\n## File: utv/foo.yaml\nversion: \"1\"", joined)
+
+	split, err := SplitFiles(joined)
+	assert.NoError(t, err)
+	assert.Equal(t, []File{
+		{Name: "about.json", Content: "{\n  \"cluster\": \"utv\"\n}"},
+		{Name: "utv/foo.yaml", Content: "version: \"1\""},
+	}, split)
+
+	_, err = SplitFiles("{}\n## File: about.json\n{}")
+	assert.EqualError(t, err, "Content must start with a line like '## File: <name>'")
+}
+
+func TestEditor_EditFiles(t *testing.T) {
+	files := []File{{Name: "a.json", Content: "{}"}, {Name: "b.json", Content: "{}"}}
+	var saved string
+	fileEditor := NewEditor(func(modifiedContent string) error {
+		saved = modifiedContent
+		return nil
+	})
+	fileEditor.OpenEditor = func(tempFile string) error {
+		data, err := ioutil.ReadFile(tempFile)
+		if err != nil {
+			t.Error(err)
+		}
+		edited := strings.Replace(string(data), "## File: b.json\n{}", "## File: b.json\n## a comment\n{\"foo\": \"bar\"}", 1)
+		return ioutil.WriteFile(tempFile, []byte(edited), 0700)
+	}
+
+	err := fileEditor.EditFiles(files)
+	assert.NoError(t, err)
+	assert.Equal(t, "## File: a.json\n{}\n\n## File: b.json\n{\"foo\": \"bar\"}", saved)
+}
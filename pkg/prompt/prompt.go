@@ -34,3 +34,17 @@ func Confirm(message string, defaultAnswer bool) bool {
 	}
 	return update
 }
+
+// Input prompts user for a required value
+func Input(message string) string {
+	p := &survey.Input{
+		Message: message,
+	}
+
+	var answer string
+	err := survey.AskOne(p, &answer, survey.Required)
+	if err != nil {
+		logrus.Error(err)
+	}
+	return answer
+}
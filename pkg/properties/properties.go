@@ -0,0 +1,269 @@
+package properties
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// entry is a key and value spanning the lines from start to end, inclusive
+type entry struct {
+	key   string
+	value string
+	start int
+	end   int
+	// prefix is the first line up to the value, keeping the indentation and separator of the key
+	prefix string
+}
+
+// Get returns the value of key in content, which is in the .properties format.
+// If key is given more than once, the last value is returned, as when properties are loaded.
+func Get(content, key string) (string, bool) {
+	entries := parse(splitLines(content))
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].key == key {
+			return entries[i].value, true
+		}
+	}
+	return "", false
+}
+
+// Set sets the value of key in content, keeping comments, ordering and the formatting and line endings of other lines.
+// An existing key is changed where it is, and a new key is added at the end. Characters outside of ASCII are
+// written as \uXXXX escapes, since properties files are read as ISO-8859-1.
+func Set(content, key, value string) string {
+	lines := splitLines(content)
+	entries := parse(lines)
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].key == key {
+			e := entries[i]
+			replaced := append([]string{}, lines[:e.start]...)
+			replaced = append(replaced, e.prefix+escape(value, false)+lineEnding(lines[e.end]))
+			return joinLines(append(replaced, lines[e.end+1:]...), content)
+		}
+	}
+
+	for len(lines) > 0 && strings.TrimSuffix(lines[len(lines)-1], "\r") == "" {
+		lines = lines[:len(lines)-1]
+	}
+	ending := ""
+	if strings.Contains(content, "\r\n") {
+		if len(lines) > 0 && lineEnding(lines[len(lines)-1]) == "" {
+			lines[len(lines)-1] += "\r"
+		}
+		if strings.HasSuffix(content, "\n") {
+			ending = "\r"
+		}
+	}
+	return joinLines(append(lines, escape(key, true)+"="+escape(value, false)+ending), content)
+}
+
+// Unset removes every line of key in content. It returns false if content has no key.
+func Unset(content, key string) (string, bool) {
+	lines := splitLines(content)
+	removed := make(map[int]bool)
+	for _, e := range parse(lines) {
+		if e.key == key {
+			for i := e.start; i <= e.end; i++ {
+				removed[i] = true
+			}
+		}
+	}
+	if len(removed) == 0 {
+		return content, false
+	}
+
+	var kept []string
+	for i, line := range lines {
+		if !removed[i] {
+			kept = append(kept, line)
+		}
+	}
+	return joinLines(kept, content), true
+}
+
+// parse finds the entries in lines, which may end with the \r of a CRLF line ending
+func parse(crlfLines []string) []entry {
+	lines := make([]string, len(crlfLines))
+	for i, line := range crlfLines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+
+	var entries []entry
+	for i := 0; i < len(lines); i++ {
+		trimmed := strings.TrimLeft(lines[i], " \t\f")
+		if trimmed == "" || trimmed[0] == '#' || trimmed[0] == '!' {
+			continue
+		}
+
+		start := i
+		logical := trimmed
+		for continues(logical) && i+1 < len(lines) {
+			i++
+			logical = logical[:len(logical)-1] + strings.TrimLeft(lines[i], " \t\f")
+		}
+		if continues(logical) {
+			logical = logical[:len(logical)-1]
+		}
+
+		key, valueStart := splitKey(logical)
+		prefixLength := len(lines[start]) - len(trimmed) + valueStart
+		if prefixLength > len(lines[start]) {
+			prefixLength = len(lines[start])
+		}
+		prefix := lines[start][:prefixLength]
+		if valueStart == len(key) {
+			prefix += "="
+		}
+		entries = append(entries, entry{
+			key:    unescape(key),
+			value:  unescape(logical[valueStart:]),
+			start:  start,
+			end:    i,
+			prefix: prefix,
+		})
+	}
+	return entries
+}
+
+// splitKey returns the escaped key of line, and the index of its value
+func splitKey(line string) (string, int) {
+	end := len(line)
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.ContainsRune("=: \t\f", rune(line[i])) {
+			end = i
+			break
+		}
+	}
+
+	valueStart := end
+	for valueStart < len(line) && strings.ContainsRune(" \t\f", rune(line[valueStart])) {
+		valueStart++
+	}
+	if valueStart < len(line) && (line[valueStart] == '=' || line[valueStart] == ':') {
+		valueStart++
+		for valueStart < len(line) && strings.ContainsRune(" \t\f", rune(line[valueStart])) {
+			valueStart++
+		}
+	}
+	return line[:end], valueStart
+}
+
+// continues returns true if line ends with an odd number of backslashes
+func continues(line string) bool {
+	count := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		count++
+	}
+	return count%2 == 1
+}
+
+func unescape(text string) string {
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		if text[i] != '\\' || i+1 == len(text) {
+			b.WriteByte(text[i])
+			continue
+		}
+		i++
+		switch text[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			code, ok := parseUnicodeEscape(text, i)
+			if !ok {
+				b.WriteByte('u')
+				break
+			}
+			i += 4
+			// Characters outside the Basic Multilingual Plane are escaped as a surrogate pair
+			if utf16.IsSurrogate(code) && i+2 < len(text) && text[i+1] == '\\' && text[i+2] == 'u' {
+				if low, ok := parseUnicodeEscape(text, i+2); ok {
+					if pair := utf16.DecodeRune(code, low); pair != '\uFFFD' {
+						code = pair
+						i += 6
+					}
+				}
+			}
+			b.WriteRune(code)
+		default:
+			b.WriteByte(text[i])
+		}
+	}
+	return b.String()
+}
+
+// parseUnicodeEscape parses the four hex digits following the u at index i of text
+func parseUnicodeEscape(text string, i int) (rune, bool) {
+	if i+5 > len(text) {
+		return 0, false
+	}
+	code, err := strconv.ParseUint(text[i+1:i+5], 16, 32)
+	if err != nil {
+		return 0, false
+	}
+	return rune(code), true
+}
+
+func escape(text string, isKey bool) string {
+	var b strings.Builder
+	for i, r := range text {
+		switch {
+		case r == '\\':
+			b.WriteString(`\\`)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r == '\f':
+			b.WriteString(`\f`)
+		case r == ' ' && (isKey || i == 0):
+			b.WriteString(`\ `)
+		case isKey && strings.ContainsRune("=:#!", r):
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case r < 0x20 || r > 0x7e:
+			for _, unit := range utf16.Encode([]rune{r}) {
+				fmt.Fprintf(&b, "\\u%04x", unit)
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// splitLines splits content at newlines, keeping the \r of CRLF line endings in the lines
+func splitLines(content string) []string {
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+}
+
+// lineEnding returns the \r of a line with a CRLF line ending, or an empty string
+func lineEnding(line string) string {
+	if strings.HasSuffix(line, "\r") {
+		return "\r"
+	}
+	return ""
+}
+
+// joinLines joins lines, ending with a newline if original does
+func joinLines(lines []string, original string) string {
+	joined := strings.Join(lines, "\n")
+	if strings.HasSuffix(original, "\n") || original == "" {
+		joined += "\n"
+	}
+	return joined
+}
@@ -0,0 +1,103 @@
+package properties
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testProperties = `# Database
+db.user = app
+db.password=secret
+! legacy
+api.key: abc\
+    def
+empty
+path=C:\\temp
+name=\u00e6ble
+`
+
+func TestGet(t *testing.T) {
+	tests := map[string]string{
+		"db.user":     "app",
+		"db.password": "secret",
+		"api.key":     "abcdef",
+		"empty":       "",
+		"path":        `C:\temp`,
+		"name":        "æble",
+	}
+	for key, expected := range tests {
+		value, ok := Get(testProperties, key)
+		assert.True(t, ok, key)
+		assert.Equal(t, expected, value, key)
+	}
+
+	_, ok := Get(testProperties, "legacy")
+	assert.False(t, ok)
+
+	value, _ := Get("a=1\na=2", "a")
+	assert.Equal(t, "2", value)
+}
+
+func TestSet(t *testing.T) {
+	changed := Set(testProperties, "db.password", `n3w\pass`)
+	assert.Equal(t, `# Database
+db.user = app
+db.password=n3w\\pass
+! legacy
+api.key: abc\
+    def
+empty
+path=C:\\temp
+name=\u00e6ble
+`, changed)
+	value, _ := Get(changed, "db.password")
+	assert.Equal(t, `n3w\pass`, value)
+
+	changed = Set(testProperties, "api.key", "xyz")
+	assert.Contains(t, changed, "! legacy\napi.key: xyz\nempty\n")
+
+	changed = Set(testProperties, "empty", " x")
+	assert.Contains(t, changed, "\nempty=\\ x\n")
+
+	assert.Equal(t, testProperties+"new.key=value\n", Set(testProperties, "new.key", "value"))
+	assert.Equal(t, "a\\=b=1\n", Set("", "a=b", "1"))
+	assert.Equal(t, "a=1\nb=2", Set("a=1", "b", "2"))
+}
+
+func TestSetNonASCII(t *testing.T) {
+	changed := Set("", "name", "æble 😀")
+	assert.Equal(t, "name=\\u00e6ble \\ud83d\\ude00\n", changed)
+
+	value, _ := Get(changed, "name")
+	assert.Equal(t, "æble 😀", value)
+}
+
+func TestSetCRLF(t *testing.T) {
+	content := "# Database\r\ndb.user=app\r\ndb.password=secret\r\n"
+
+	assert.Equal(t, "# Database\r\ndb.user=app\r\ndb.password=changed\r\n", Set(content, "db.password", "changed"))
+	assert.Equal(t, content+"new.key=value\r\n", Set(content, "new.key", "value"))
+	assert.Equal(t, "a=1\r\nb=2\r\nc=3", Set("a=1\r\nb=2", "c", "3"))
+	unset, _ := Unset(content, "db.user")
+	assert.Equal(t, "# Database\r\ndb.password=secret\r\n", unset)
+
+	value, _ := Get(content, "db.user")
+	assert.Equal(t, "app", value)
+}
+
+func TestUnset(t *testing.T) {
+	changed, ok := Unset(testProperties, "api.key")
+	assert.True(t, ok)
+	assert.Equal(t, `# Database
+db.user = app
+db.password=secret
+! legacy
+empty
+path=C:\\temp
+name=\u00e6ble
+`, changed)
+
+	_, ok = Unset(testProperties, "missing")
+	assert.False(t, ok)
+}
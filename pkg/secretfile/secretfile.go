@@ -0,0 +1,96 @@
+package secretfile
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Extensions of encrypted secret files
+const (
+	AgeExtension = ".age"
+	GPGExtension = ".gpg"
+)
+
+// AgeIdentityEnv is the environment variable with the path of the age identity file used for decryption
+const AgeIdentityEnv = "AO_AGE_IDENTITY"
+
+// IsAgeRecipient returns true if recipient is an age or ssh public key, and false if it is an OpenPGP key id or email
+func IsAgeRecipient(recipient string) bool {
+	return strings.HasPrefix(recipient, "age1") || strings.HasPrefix(recipient, "ssh-")
+}
+
+// Encrypt encrypts content to recipients, which are either all age keys or all OpenPGP keys.
+// It returns the encrypted content and the extension to add to the file name.
+func Encrypt(content []byte, recipients []string) ([]byte, string, error) {
+	if len(recipients) == 0 {
+		return nil, "", errors.New("No recipients to encrypt to")
+	}
+	useAge := IsAgeRecipient(recipients[0])
+	var args []string
+	for _, recipient := range recipients {
+		if IsAgeRecipient(recipient) != useAge {
+			return nil, "", errors.New("Can not encrypt to both age and OpenPGP recipients")
+		}
+		args = append(args, "--recipient", recipient)
+	}
+
+	if useAge {
+		encrypted, err := run("age", content, append([]string{"--encrypt"}, args...)...)
+		return encrypted, AgeExtension, err
+	}
+	encrypted, err := run("gpg", content, append([]string{"--batch", "--yes", "--encrypt"}, args...)...)
+	return encrypted, GPGExtension, err
+}
+
+// IsEncrypted returns true if fileName has the extension of an encrypted file
+func IsEncrypted(fileName string) bool {
+	extension := path.Ext(fileName)
+	return extension == AgeExtension || extension == GPGExtension
+}
+
+// SecretName returns the name of the secret in fileName, without the extension of an encrypted file
+func SecretName(fileName string) string {
+	if IsEncrypted(fileName) {
+		return strings.TrimSuffix(fileName, path.Ext(fileName))
+	}
+	return fileName
+}
+
+// Decrypt decrypts the content of fileName. Age files are decrypted with identity, or the identity file
+// in AgeIdentityEnv if it is empty, and OpenPGP files with the keys of the local gpg keyring.
+func Decrypt(fileName string, content []byte, identity string) ([]byte, error) {
+	switch path.Ext(fileName) {
+	case AgeExtension:
+		if identity == "" {
+			identity = os.Getenv(AgeIdentityEnv)
+		}
+		if identity == "" {
+			return nil, errors.Errorf("An age identity is needed to decrypt %s, use --identity or %s", fileName, AgeIdentityEnv)
+		}
+		return run("age", content, "--decrypt", "--identity", identity)
+	case GPGExtension:
+		return run("gpg", content, "--batch", "--quiet", "--decrypt")
+	}
+	return content, nil
+}
+
+func run(name string, stdin []byte, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = err.Error()
+		}
+		return nil, errors.Errorf("%s failed: %s", name, message)
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,120 @@
+package secretfile
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecretName(t *testing.T) {
+	assert.True(t, IsEncrypted("latest.properties.age"))
+	assert.True(t, IsEncrypted("latest.properties.gpg"))
+	assert.False(t, IsEncrypted("latest.properties"))
+	assert.Equal(t, "latest.properties", SecretName("latest.properties.gpg"))
+	assert.Equal(t, "latest.properties", SecretName("latest.properties"))
+}
+
+func TestEncryptWithMixedRecipients(t *testing.T) {
+	_, _, err := Encrypt([]byte("FOO=BAR"), []string{"age1abc", "ops@example.com"})
+	assert.EqualError(t, err, "Can not encrypt to both age and OpenPGP recipients")
+}
+
+func TestDecryptAgeWithoutIdentity(t *testing.T) {
+	os.Unsetenv(AgeIdentityEnv)
+	_, err := Decrypt("latest.properties.age", []byte{}, "")
+	assert.EqualError(t, err, "An age identity is needed to decrypt latest.properties.age, use --identity or "+AgeIdentityEnv)
+}
+
+func TestEncryptAndDecryptWithGPG(t *testing.T) {
+	if _, err := exec.LookPath("gpg"); err != nil {
+		t.Skip("gpg is not installed")
+	}
+	home, err := ioutil.TempDir("", "ao_gnupg_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+	os.Setenv("GNUPGHOME", home)
+	defer os.Unsetenv("GNUPGHOME")
+	defer run("gpgconf", nil, "--kill", "gpg-agent")
+
+	if _, err := run("gpg", nil, "--batch", "--passphrase", "", "--quick-gen-key", "ao-test@example.com", "default", "default", "never"); err != nil {
+		t.Fatal(err)
+	}
+
+	encrypted, extension, err := Encrypt([]byte("FOO=BAR"), []string{"ao-test@example.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, GPGExtension, extension)
+	assert.NotContains(t, string(encrypted), "FOO=BAR")
+
+	decrypted, err := Decrypt(path.Join("vault", "latest.properties"+extension), encrypted, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "FOO=BAR", string(decrypted))
+}
+
+func TestEncryptWithUntrustedGPGKey(t *testing.T) {
+	if _, err := exec.LookPath("gpg"); err != nil {
+		t.Skip("gpg is not installed")
+	}
+	owner, err := ioutil.TempDir("", "ao_gnupg_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(owner)
+	home, err := ioutil.TempDir("", "ao_gnupg_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+	defer os.Unsetenv("GNUPGHOME")
+
+	os.Setenv("GNUPGHOME", owner)
+	if _, err := run("gpg", nil, "--batch", "--passphrase", "", "--quick-gen-key", "ao-test@example.com", "default", "default", "never"); err != nil {
+		t.Fatal(err)
+	}
+	publicKey, err := run("gpg", nil, "--batch", "--export", "--armor", "ao-test@example.com")
+	run("gpgconf", nil, "--kill", "gpg-agent")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	os.Setenv("GNUPGHOME", home)
+	defer run("gpgconf", nil, "--kill", "gpg-agent")
+	if _, err := run("gpg", publicKey, "--batch", "--import"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, err = Encrypt([]byte("FOO=BAR"), []string{"ao-test@example.com"})
+	assert.Error(t, err)
+}
+
+func TestEncryptAndDecryptWithAge(t *testing.T) {
+	if _, err := exec.LookPath("age-keygen"); err != nil {
+		t.Skip("age is not installed")
+	}
+	folder, err := ioutil.TempDir("", "ao_age_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(folder)
+	identity := path.Join(folder, "keys.txt")
+	if _, err := run("age-keygen", nil, "-o", identity); err != nil {
+		t.Fatal(err)
+	}
+	recipient, err := run("age-keygen", nil, "-y", identity)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encrypted, extension, err := Encrypt([]byte("FOO=BAR"), []string{string(recipient[:len(recipient)-1])})
+	assert.NoError(t, err)
+	assert.Equal(t, AgeExtension, extension)
+
+	decrypted, err := Decrypt("latest.properties"+extension, encrypted, identity)
+	assert.NoError(t, err)
+	assert.Equal(t, "FOO=BAR", string(decrypted))
+}
@@ -19,3 +19,37 @@ func GetApplications(apiClient client.AuroraConfigClient, pattern string, exclud
 
 	return applications, nil
 }
+
+// LoadGroups returns the groups defined in the group file of the AuroraConfig, and in configGroups.
+// Groups in configGroups replace groups with the same name in the group file.
+func LoadGroups(apiClient client.AuroraConfigClient, filenames auroraconfig.FileNames, configGroups map[string][]string, configSource string) (auroraconfig.Groups, error) {
+	groups := auroraconfig.Groups{}
+	if groupFile := filenames.FindGroupFile(); groupFile != "" {
+		file, _, err := apiClient.GetAuroraConfigFile(groupFile)
+		if err != nil {
+			return nil, err
+		}
+		fileGroups, err := auroraconfig.ParseGroupFile(file)
+		if err != nil {
+			return nil, err
+		}
+		groups.Add(groupFile, fileGroups)
+	}
+	groups.Add(configSource, configGroups)
+	return groups, nil
+}
+
+// GetGroupApplications returns list of applications in a group
+func GetGroupApplications(apiClient client.AuroraConfigClient, group string, configGroups map[string][]string, configSource string, excludes []string) ([]string, error) {
+	filenames, err := apiClient.GetFileNames()
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := LoadGroups(apiClient, filenames, configGroups, configSource)
+	if err != nil {
+		return nil, err
+	}
+
+	return auroraconfig.GetGroupApplicationRefs(groups, filenames, group, excludes)
+}
@@ -45,3 +45,30 @@ func Test_getApplicationsWithExclusions(t *testing.T) {
 	assert.Contains(t, actualApplications, "test-qa/crm")
 	assert.Contains(t, actualApplications, "test-st/crm-2-GA")
 }
+
+func Test_getApplicationsWithoutSearch(t *testing.T) {
+	apiClient := client.NewAuroraConfigClientMock(fileNames[:])
+
+	actualApplications, err := GetApplications(apiClient, "", []string{"prod/.*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, actualApplications, 11)
+	assert.NotContains(t, actualApplications, "prod/crm")
+}
+
+func Test_getGroupApplications(t *testing.T) {
+	apiClient := client.NewAuroraConfigClientMock(fileNames[:])
+	configGroups := map[string][]string{
+		"crm":  {"dev/crm", "test-qa/crm", "prod/crm"},
+		"prod": {"@crm", "prod/booking"},
+	}
+
+	actualApplications, err := GetGroupApplications(apiClient, "@prod", configGroups, ".ao-config.json", []string{"dev/.*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []string{"test-qa/crm", "prod/crm", "prod/booking"}, actualApplications)
+}
@@ -0,0 +1,175 @@
+package versioncontrol
+
+import (
+	"bytes"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Remote is the git remote refs are listed from and pushed to
+const Remote = "origin"
+
+// ListRefs lists the branches of the remote AuroraConfig repository
+func ListRefs(gitRoot string) ([]string, error) {
+	output, err := runGit(gitRoot, "ls-remote", "--heads", Remote)
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []string
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 {
+			refs = append(refs, strings.TrimPrefix(fields[1], "refs/heads/"))
+		}
+	}
+	sort.Strings(refs)
+	return refs, nil
+}
+
+// CreateRef creates a branch in the remote repository from the current head of another branch
+func CreateRef(gitRoot, name, from string) error {
+	if err := fetch(gitRoot); err != nil {
+		return err
+	}
+	if remoteRefExists(gitRoot, name) {
+		return errors.Errorf("ref %s already exists", name)
+	}
+	if !remoteRefExists(gitRoot, from) {
+		return errors.Errorf("ref %s does not exist", from)
+	}
+
+	_, err := runGit(gitRoot, "push", Remote, remoteRef(from)+":refs/heads/"+name)
+	return err
+}
+
+// DiffRefs returns the changes made in name since it was branched from from.
+// With nameOnly only the status and names of the changed files are returned.
+func DiffRefs(gitRoot, from, name string, nameOnly bool) (string, error) {
+	if err := fetch(gitRoot); err != nil {
+		return "", err
+	}
+	for _, ref := range []string{from, name} {
+		if !remoteRefExists(gitRoot, ref) {
+			return "", errors.Errorf("ref %s does not exist", ref)
+		}
+	}
+
+	args := []string{"diff"}
+	if nameOnly {
+		args = append(args, "--name-status")
+	}
+	return runGit(gitRoot, append(args, remoteRef(from)+"..."+remoteRef(name))...)
+}
+
+// MergeRef merges name into the branch into in the local checkout, without pushing it. The local branch into
+// is reset to the remote branch first, which fails if it has commits that are not in the remote branch.
+// If the merge has conflicts it is aborted, and the conflicting files are returned.
+// The checkout is left on into, use CurrentRef before and CheckoutRef after to get back to where it was.
+func MergeRef(gitRoot, name, into string) ([]string, error) {
+	status, err := runGit(gitRoot, "status", "--porcelain", "--untracked-files=no")
+	if err != nil {
+		return nil, err
+	}
+	if status != "" {
+		return nil, errors.New("Local checkout has uncommitted changes, commit or stash them before merging")
+	}
+	if err := fetch(gitRoot); err != nil {
+		return nil, err
+	}
+	for _, ref := range []string{name, into} {
+		if !remoteRefExists(gitRoot, ref) {
+			return nil, errors.Errorf("ref %s does not exist", ref)
+		}
+	}
+
+	if localRefExists(gitRoot, into) {
+		unpushed, err := runGit(gitRoot, "rev-list", "--count", remoteRef(into)+".."+"refs/heads/"+into)
+		if err != nil {
+			return nil, err
+		}
+		if unpushed != "0" {
+			return nil, errors.Errorf("Local branch %s has %s commit(s) that are not in %s/%s, push or remove them before merging", into, unpushed, Remote, into)
+		}
+	}
+
+	if _, err := runGit(gitRoot, "checkout", "-B", into, remoteRef(into)); err != nil {
+		return nil, err
+	}
+	if _, err := runGit(gitRoot, "merge", "--no-ff", "--no-edit", remoteRef(name)); err != nil {
+		conflicts, diffErr := runGit(gitRoot, "diff", "--name-only", "--diff-filter=U")
+		if diffErr != nil || conflicts == "" {
+			runGit(gitRoot, "merge", "--abort")
+			return nil, err
+		}
+		if _, abortErr := runGit(gitRoot, "merge", "--abort"); abortErr != nil {
+			return nil, abortErr
+		}
+		return strings.Split(conflicts, "\n"), nil
+	}
+	return nil, nil
+}
+
+// CurrentRef returns the branch checked out in the local checkout, or the commit if no branch is checked out
+func CurrentRef(gitRoot string) (string, error) {
+	if branch, err := runGit(gitRoot, "symbolic-ref", "--quiet", "--short", "HEAD"); err == nil {
+		return branch, nil
+	}
+	return runGit(gitRoot, "rev-parse", "HEAD")
+}
+
+// CheckoutRef checks out a branch or commit returned by CurrentRef
+func CheckoutRef(gitRoot, ref string) error {
+	_, err := runGit(gitRoot, "checkout", "--quiet", ref)
+	return err
+}
+
+// PushRef pushes a local branch to the remote repository
+func PushRef(gitRoot, name string) error {
+	_, err := runGit(gitRoot, "push", Remote, name)
+	return err
+}
+
+// UndoMerge resets the current branch to where it was before the last merge
+func UndoMerge(gitRoot string) error {
+	_, err := runGit(gitRoot, "reset", "--hard", "ORIG_HEAD")
+	return err
+}
+
+func fetch(gitRoot string) error {
+	_, err := runGit(gitRoot, "fetch", "--prune", Remote)
+	return err
+}
+
+func remoteRef(name string) string {
+	return "refs/remotes/" + Remote + "/" + name
+}
+
+func localRefExists(gitRoot, name string) bool {
+	_, err := runGit(gitRoot, "rev-parse", "--verify", "--quiet", "refs/heads/"+name)
+	return err == nil
+}
+
+func remoteRefExists(gitRoot, name string) bool {
+	_, err := runGit(gitRoot, "rev-parse", "--verify", "--quiet", remoteRef(name))
+	return err == nil
+}
+
+func runGit(gitRoot string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Dir = gitRoot
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = strings.TrimSpace(stdout.String())
+		}
+		return "", errors.Errorf("git %s failed: %s", args[0], message)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
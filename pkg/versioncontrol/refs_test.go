@@ -0,0 +1,155 @@
+package versioncontrol
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	refsOriginPath = "/tmp/ao/testRefsOrigin"
+	refsRepoPath   = "/tmp/ao/testRefsRepo"
+)
+
+func refsSetup(t *testing.T) {
+	for _, env := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		os.Setenv(env, "ao")
+	}
+	for _, env := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		os.Setenv(env, "ao@example.com")
+	}
+
+	os.RemoveAll(refsOriginPath)
+	os.RemoveAll(refsRepoPath)
+	os.MkdirAll(refsOriginPath, 0755)
+	os.MkdirAll(refsRepoPath, 0755)
+
+	mustGit(t, refsOriginPath, "init", "--bare")
+	mustGit(t, refsRepoPath, "init")
+	mustGit(t, refsRepoPath, "checkout", "-B", "master")
+	mustGit(t, refsRepoPath, "remote", "add", Remote, refsOriginPath)
+	commitFile(t, "about.json", `{"cluster": "utv"}`)
+	mustGit(t, refsRepoPath, "push", Remote, "master")
+}
+
+func mustGit(t *testing.T, dir string, args ...string) {
+	if _, err := runGit(dir, args...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func commitFile(t *testing.T, name, contents string) {
+	if err := ioutil.WriteFile(filepath.Join(refsRepoPath, name), []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mustGit(t, refsRepoPath, "add", name)
+	mustGit(t, refsRepoPath, "commit", "-m", "Update "+name)
+}
+
+func TestCreateAndListRefs(t *testing.T) {
+	refsSetup(t)
+
+	err := CreateRef(refsRepoPath, "restructure", "master")
+	assert.NoError(t, err)
+
+	refs, err := ListRefs(refsRepoPath)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"master", "restructure"}, refs)
+
+	err = CreateRef(refsRepoPath, "restructure", "master")
+	assert.EqualError(t, err, "ref restructure already exists")
+
+	err = CreateRef(refsRepoPath, "other", "missing")
+	assert.EqualError(t, err, "ref missing does not exist")
+}
+
+func TestDiffAndMergeRef(t *testing.T) {
+	refsSetup(t)
+	assert.NoError(t, CreateRef(refsRepoPath, "restructure", "master"))
+
+	mustGit(t, refsRepoPath, "checkout", "-B", "restructure", remoteRef("restructure"))
+	commitFile(t, "foo.json", `{"version": "1"}`)
+	mustGit(t, refsRepoPath, "push", Remote, "restructure")
+
+	diff, err := DiffRefs(refsRepoPath, "master", "restructure", true)
+	assert.NoError(t, err)
+	assert.Equal(t, "A\tfoo.json", diff)
+
+	conflicts, err := MergeRef(refsRepoPath, "restructure", "master")
+	assert.NoError(t, err)
+	assert.Empty(t, conflicts)
+	assert.NoError(t, PushRef(refsRepoPath, "master"))
+
+	diff, err = DiffRefs(refsRepoPath, "restructure", "master", false)
+	assert.NoError(t, err)
+	assert.Empty(t, diff)
+}
+
+func TestMergeRefWithConflicts(t *testing.T) {
+	refsSetup(t)
+	assert.NoError(t, CreateRef(refsRepoPath, "restructure", "master"))
+
+	commitFile(t, "about.json", `{"cluster": "test"}`)
+	mustGit(t, refsRepoPath, "push", Remote, "master")
+	mustGit(t, refsRepoPath, "checkout", "-B", "restructure", remoteRef("restructure"))
+	commitFile(t, "about.json", `{"cluster": "prod"}`)
+	mustGit(t, refsRepoPath, "push", Remote, "restructure")
+
+	conflicts, err := MergeRef(refsRepoPath, "restructure", "master")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"about.json"}, conflicts)
+
+	status, err := runGit(refsRepoPath, "status", "--porcelain")
+	assert.NoError(t, err)
+	assert.Empty(t, status)
+}
+
+func TestMergeRefWithUnpushedCommits(t *testing.T) {
+	refsSetup(t)
+	assert.NoError(t, CreateRef(refsRepoPath, "restructure", "master"))
+	mustGit(t, refsRepoPath, "checkout", "-B", "restructure", remoteRef("restructure"))
+	commitFile(t, "foo.json", `{"version": "1"}`)
+	mustGit(t, refsRepoPath, "push", Remote, "restructure")
+
+	mustGit(t, refsRepoPath, "checkout", "master")
+	commitFile(t, "bar.json", `{"version": "2"}`)
+	head, err := runGit(refsRepoPath, "rev-parse", "master")
+	assert.NoError(t, err)
+	mustGit(t, refsRepoPath, "checkout", "restructure")
+
+	_, err = MergeRef(refsRepoPath, "restructure", "master")
+	assert.EqualError(t, err, "Local branch master has 1 commit(s) that are not in origin/master, push or remove them before merging")
+
+	current, err := CurrentRef(refsRepoPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "restructure", current)
+	unchanged, err := runGit(refsRepoPath, "rev-parse", "master")
+	assert.NoError(t, err)
+	assert.Equal(t, head, unchanged)
+}
+
+func TestCurrentAndCheckoutRef(t *testing.T) {
+	refsSetup(t)
+	assert.NoError(t, CreateRef(refsRepoPath, "restructure", "master"))
+
+	original, err := CurrentRef(refsRepoPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "master", original)
+
+	conflicts, err := MergeRef(refsRepoPath, "restructure", "master")
+	assert.NoError(t, err)
+	assert.Empty(t, conflicts)
+
+	mustGit(t, refsRepoPath, "checkout", "--detach")
+	detached, err := CurrentRef(refsRepoPath)
+	assert.NoError(t, err)
+	assert.Len(t, detached, 40)
+
+	assert.NoError(t, CheckoutRef(refsRepoPath, original))
+	current, err := CurrentRef(refsRepoPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "master", current)
+}
@@ -87,6 +87,30 @@ func CollectAuroraConfigFilesInRepo(affiliation, gitRoot string) (*auroraconfig.
 	})
 }
 
+// WriteAuroraConfigFilesInRepo writes AuroraConfig files to the repo, creating folders as needed
+func WriteAuroraConfigFilesInRepo(gitRoot string, files []auroraconfig.File) error {
+	for _, file := range files {
+		path := filepath.Join(gitRoot, filepath.FromSlash(file.Name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(path, []byte(file.Contents), 0644); err != nil {
+			return errors.Wrap(err, "Could not write file "+file.Name)
+		}
+	}
+	return nil
+}
+
+// RemoveAuroraConfigFilesInRepo removes AuroraConfig files from the repo
+func RemoveAuroraConfigFilesInRepo(gitRoot string, fileNames []string) error {
+	for _, fileName := range fileNames {
+		if err := os.Remove(filepath.Join(gitRoot, filepath.FromSlash(fileName))); err != nil {
+			return errors.Wrap(err, "Could not remove file "+fileName)
+		}
+	}
+	return nil
+}
+
 // HasOneOfExtension checks text for an array of suffixes
 func HasOneOfExtension(text string, items []string) bool {
 	for _, item := range items {
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -144,6 +145,32 @@ func TestCollectJSONFilesInRepo(t *testing.T) {
 	}
 }
 
+func TestWriteAndRemoveAuroraConfigFilesInRepo(t *testing.T) {
+	repoSetup("")
+
+	files := []auroraconfig.File{
+		{Name: "about.yaml", Contents: "---\nschemaVersion: v1\n"},
+		{Name: "prod/about.yaml", Contents: "---\ncluster: prod\n"},
+	}
+	err := WriteAuroraConfigFilesInRepo(RepoPath, files)
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(fmt.Sprintf("%s/prod/about.yaml", RepoPath))
+	assert.NoError(t, err)
+	assert.Equal(t, "---\ncluster: prod\n", string(data))
+
+	err = RemoveAuroraConfigFilesInRepo(RepoPath, []string{"prod/about.yaml"})
+	assert.NoError(t, err)
+
+	ac, err := CollectAuroraConfigFilesInRepo("aurora", RepoPath)
+	assert.NoError(t, err)
+	assert.Len(t, ac.Files, 1)
+	assert.Equal(t, "about.yaml", ac.Files[0].Name)
+
+	err = RemoveAuroraConfigFilesInRepo(RepoPath, []string{"prod/about.yaml"})
+	assert.Error(t, err)
+}
+
 func TestCreateGitValidateHook(t *testing.T) {
 	repoSetup("")
 	type args struct {